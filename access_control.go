@@ -0,0 +1,272 @@
+package gapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RoleGrafanaAdmin is the built-in role of Grafana server admins. It can only
+// be used for built-in role assignments.
+const RoleGrafanaAdmin Role = "Grafana Admin"
+
+// Permission allows an action on the resources matching a scope, e.g. action
+// "dashboards:read" on scope "folders:uid:ops".
+type Permission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+// AccessControlRole is a fine-grained access control role. Access control is
+// a Grafana Enterprise feature.
+type AccessControlRole struct {
+	Version     int64        `json:"version"`
+	Uid         string       `json:"uid,omitempty"`
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Group       string       `json:"group,omitempty"`
+	Global      bool         `json:"global"`
+	Hidden      bool         `json:"hidden,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty"`
+	Created     time.Time    `json:"created,omitempty"`
+	Updated     time.Time    `json:"updated,omitempty"`
+}
+
+func (c *Client) AccessControlRoles() ([]AccessControlRole, error) {
+	roles := make([]AccessControlRole, 0)
+	err := c.accessControlJSON("AccessControlRoles", "GET", "/api/access-control/roles", nil, nil, &roles)
+	return roles, err
+}
+
+func (c *Client) AccessControlRole(uid string) (*AccessControlRole, error) {
+	role := &AccessControlRole{}
+	err := c.accessControlJSON("AccessControlRole", "GET", fmt.Sprintf("/api/access-control/roles/%s", uid), nil, nil, role)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (c *Client) NewAccessControlRole(role AccessControlRole) (*AccessControlRole, error) {
+	created := &AccessControlRole{}
+	err := c.accessControlJSON("NewAccessControlRole", "POST", "/api/access-control/roles", nil, role, created)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateAccessControlRole replaces the role with the given uid. Grafana
+// requires role.Version to be greater than the current version of the role.
+func (c *Client) UpdateAccessControlRole(role AccessControlRole) (*AccessControlRole, error) {
+	updated := &AccessControlRole{}
+	err := c.accessControlJSON("UpdateAccessControlRole", "PUT", fmt.Sprintf("/api/access-control/roles/%s", role.Uid), nil, role, updated)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteAccessControlRole deletes a role. Unless force is set, Grafana refuses
+// to delete a role that is still assigned.
+func (c *Client) DeleteAccessControlRole(uid string, global, force bool) error {
+	query := url.Values{}
+	query.Add("global", strconv.FormatBool(global))
+	query.Add("force", strconv.FormatBool(force))
+	return c.accessControlJSON("DeleteAccessControlRole", "DELETE", fmt.Sprintf("/api/access-control/roles/%s", uid), query, nil, nil)
+}
+
+// Service accounts are users as far as access control is concerned: use the
+// user methods below with the id of the service account.
+
+func (c *Client) UserRoles(userId int64) ([]AccessControlRole, error) {
+	roles := make([]AccessControlRole, 0)
+	err := c.accessControlJSON("UserRoles", "GET", fmt.Sprintf("/api/access-control/users/%d/roles", userId), nil, nil, &roles)
+	return roles, err
+}
+
+func (c *Client) AddUserRole(userId int64, roleUid string, global bool) error {
+	body := map[string]interface{}{
+		"roleUid": roleUid,
+		"global":  global,
+	}
+	return c.accessControlJSON("AddUserRole", "POST", fmt.Sprintf("/api/access-control/users/%d/roles", userId), nil, body, nil)
+}
+
+func (c *Client) RemoveUserRole(userId int64, roleUid string, global bool) error {
+	query := url.Values{}
+	query.Add("global", strconv.FormatBool(global))
+	return c.accessControlJSON("RemoveUserRole", "DELETE", fmt.Sprintf("/api/access-control/users/%d/roles/%s", userId, roleUid), query, nil, nil)
+}
+
+// SetUserRoles replaces every role assigned to the user with roleUids.
+func (c *Client) SetUserRoles(userId int64, roleUids []string, global bool) error {
+	body := map[string]interface{}{
+		"roleUids": roleUids,
+		"global":   global,
+	}
+	return c.accessControlJSON("SetUserRoles", "PUT", fmt.Sprintf("/api/access-control/users/%d/roles", userId), nil, body, nil)
+}
+
+func (c *Client) TeamRoles(teamId int64) ([]AccessControlRole, error) {
+	roles := make([]AccessControlRole, 0)
+	err := c.accessControlJSON("TeamRoles", "GET", fmt.Sprintf("/api/access-control/teams/%d/roles", teamId), nil, nil, &roles)
+	return roles, err
+}
+
+func (c *Client) AddTeamRole(teamId int64, roleUid string) error {
+	body := map[string]interface{}{
+		"roleUid": roleUid,
+	}
+	return c.accessControlJSON("AddTeamRole", "POST", fmt.Sprintf("/api/access-control/teams/%d/roles", teamId), nil, body, nil)
+}
+
+func (c *Client) RemoveTeamRole(teamId int64, roleUid string) error {
+	return c.accessControlJSON("RemoveTeamRole", "DELETE", fmt.Sprintf("/api/access-control/teams/%d/roles/%s", teamId, roleUid), nil, nil, nil)
+}
+
+// SetTeamRoles replaces every role assigned to the team with roleUids.
+func (c *Client) SetTeamRoles(teamId int64, roleUids []string) error {
+	body := map[string]interface{}{
+		"roleUids": roleUids,
+	}
+	return c.accessControlJSON("SetTeamRoles", "PUT", fmt.Sprintf("/api/access-control/teams/%d/roles", teamId), nil, body, nil)
+}
+
+// BuiltinRoleAssignments returns the roles assigned to each built-in role
+// (Viewer, Editor, Admin and Grafana Admin).
+func (c *Client) BuiltinRoleAssignments() (map[Role][]AccessControlRole, error) {
+	assignments := map[Role][]AccessControlRole{}
+	err := c.accessControlJSON("BuiltinRoleAssignments", "GET", "/api/access-control/builtin-roles", nil, nil, &assignments)
+	return assignments, err
+}
+
+func (c *Client) AddBuiltinRoleAssignment(builtinRole Role, roleUid string, global bool) error {
+	if err := validateBuiltinRole(builtinRole); err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"builtinRole": builtinRole,
+		"roleUid":     roleUid,
+		"global":      global,
+	}
+	return c.accessControlJSON("AddBuiltinRoleAssignment", "POST", "/api/access-control/builtin-roles", nil, body, nil)
+}
+
+func (c *Client) RemoveBuiltinRoleAssignment(builtinRole Role, roleUid string, global bool) error {
+	if err := validateBuiltinRole(builtinRole); err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Add("global", strconv.FormatBool(global))
+	path := fmt.Sprintf("/api/access-control/builtin-roles/%s/roles/%s", url.PathEscape(string(builtinRole)), roleUid)
+	return c.accessControlJSON("RemoveBuiltinRoleAssignment", "DELETE", path, query, nil, nil)
+}
+
+func validateBuiltinRole(role Role) error {
+	if role == RoleGrafanaAdmin {
+		return nil
+	}
+	return role.Validate()
+}
+
+// UserEffectivePermissions approximates the permissions a user has in an
+// organization from the roles assigned to the user, to the teams of the user
+// and to its built-in roles. Roles assigned to a built-in role also apply to
+// the more privileged ones, e.g. Viewer roles apply to Editors.
+//
+// It is only an approximation: the permissions Grafana grants implicitly,
+// through the basic and fixed roles it does not list as assignments or
+// through resource permissions, are missing. It also makes a request per
+// team and per role listed without its permissions, so it is slow for users
+// in many teams.
+//
+// The result is sorted by action and scope and holds no duplicates.
+func (c *Client) UserEffectivePermissions(userId, orgId int64) ([]Permission, error) {
+	oc := c.WithOrgID(orgId)
+
+	user, err := oc.UserByID(userId)
+	if err != nil {
+		return nil, err
+	}
+	orgs, err := oc.UserOrgs(userId)
+	if err != nil {
+		return nil, err
+	}
+	orgRole := Role("")
+	for _, org := range orgs {
+		if org.OrgId == orgId {
+			orgRole = org.Role
+		}
+	}
+
+	assigned, err := oc.UserRoles(userId)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := oc.UserTeams(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		if team.OrgId != 0 && team.OrgId != orgId {
+			continue
+		}
+		roles, err := oc.TeamRoles(team.Id)
+		if err != nil {
+			return nil, err
+		}
+		assigned = append(assigned, roles...)
+	}
+	builtin, err := oc.BuiltinRoleAssignments()
+	if err != nil {
+		return nil, err
+	}
+	for builtinRole, roles := range builtin {
+		if (builtinRole == RoleGrafanaAdmin && user.IsGrafanaAdmin) || orgRole.AtLeast(builtinRole) {
+			assigned = append(assigned, roles...)
+		}
+	}
+
+	seenRoles := map[string]bool{}
+	seen := map[Permission]bool{}
+	permissions := make([]Permission, 0)
+	for _, role := range assigned {
+		if seenRoles[role.Uid] {
+			continue
+		}
+		seenRoles[role.Uid] = true
+
+		// Role lists do not always include the permissions of each role.
+		if role.Permissions == nil {
+			full, err := oc.AccessControlRole(role.Uid)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to get role %s", role.Uid)
+			}
+			role = *full
+		}
+		for _, p := range role.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].Action != permissions[j].Action {
+			return permissions[i].Action < permissions[j].Action
+		}
+		return permissions[i].Scope < permissions[j].Scope
+	})
+	return permissions, nil
+}
+
+// accessControlJSON is sendJSON for endpoints that need CapabilityAccessControl.
+func (c *Client) accessControlJSON(name, method, path string, query url.Values, body, result interface{}) error {
+	return c.explainNotFound(c.sendJSON(name, method, path, query, body, result), CapabilityAccessControl)
+}
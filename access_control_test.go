@@ -0,0 +1,131 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getAccessControlRolesJSON = `[{"version":1,"uid":"custom_reader","name":"custom:reports:reader","displayName":"Report reader","global":false,"updated":"2021-05-17T22:07:31.569936+02:00","created":"2021-05-13T16:24:26.468Z"}]`
+	getAccessControlRoleJSON  = `{"version":2,"uid":"custom_reader","name":"custom:reports:reader","global":false,"permissions":[{"action":"reports:read","scope":"reports:*"}]}`
+	accessControlActionJSON   = `{"message":"Role added to the user."}`
+)
+
+func TestAccessControlRoles(t *testing.T) {
+	server, client := gapiTestTools(200, getAccessControlRolesJSON)
+	defer server.Close()
+
+	roles, err := client.AccessControlRoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(roles))
+
+	if len(roles) != 1 || roles[0].Uid != "custom_reader" || roles[0].DisplayName != "Report reader" {
+		t.Error("Not correctly parsing returned roles.")
+	}
+}
+
+func TestAccessControlRole(t *testing.T) {
+	server, client := gapiTestTools(200, getAccessControlRoleJSON)
+	defer server.Close()
+
+	role, err := client.AccessControlRole("custom_reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if role.Version != 2 || len(role.Permissions) != 1 || role.Permissions[0] != (Permission{"reports:read", "reports:*"}) {
+		t.Error("Not correctly parsing returned role.")
+	}
+}
+
+func TestNewAccessControlRole(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := AccessControlRole{}
+		json.NewDecoder(r.Body).Decode(&role)
+		if role.Name != "custom:reports:reader" || len(role.Permissions) != 1 {
+			t.Errorf("Unexpected role sent %v", role)
+		}
+		fmt.Fprint(w, getAccessControlRoleJSON)
+	}))
+	defer server.Close()
+
+	role, err := client.NewAccessControlRole(AccessControlRole{
+		Version:     1,
+		Name:        "custom:reports:reader",
+		Permissions: []Permission{{Action: "reports:read", Scope: "reports:*"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role.Uid != "custom_reader" {
+		t.Error("Not correctly parsing created role.")
+	}
+}
+
+func TestAddUserRole(t *testing.T) {
+	server, client := gapiTestTools(200, accessControlActionJSON)
+	defer server.Close()
+
+	err := client.AddUserRole(1, "custom_reader", false)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAddBuiltinRoleAssignmentInvalidRole(t *testing.T) {
+	server, client := gapiTestTools(200, accessControlActionJSON)
+	defer server.Close()
+
+	if err := client.AddBuiltinRoleAssignment("viewer", "custom_reader", false); err == nil {
+		t.Error("Invalid built-in roles should be rejected")
+	}
+	if err := client.AddBuiltinRoleAssignment(RoleGrafanaAdmin, "custom_reader", true); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserEffectivePermissions(t *testing.T) {
+	responses := map[string]string{
+		"/api/users/2":                            `{"id":2,"login":"editor","isGrafanaAdmin":false}`,
+		"/api/users/2/orgs":                       `[{"orgId":1,"name":"Main Org.","role":"Editor"}]`,
+		"/api/users/2/teams":                      `[{"id":7,"orgId":1,"name":"reporting"}]`,
+		"/api/access-control/users/2/roles":       `[{"uid":"direct","permissions":[{"action":"dashboards:read","scope":"dashboards:*"}]}]`,
+		"/api/access-control/teams/7/roles":       `[{"uid":"custom_reader"}]`,
+		"/api/access-control/roles/custom_reader": getAccessControlRoleJSON,
+		"/api/access-control/builtin-roles":       `{"Viewer":[{"uid":"viewer_role","permissions":[{"action":"dashboards:read","scope":"dashboards:*"}]}],"Admin":[{"uid":"admin_role","permissions":[{"action":"users:write"}]}],"Grafana Admin":[{"uid":"server_role","permissions":[{"action":"orgs:create"}]}]}`,
+	}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(404)
+			return
+		}
+		if r.Header.Get("X-Grafana-Org-Id") != "1" {
+			t.Errorf("Request %s should be scoped to org 1", r.URL.Path)
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	permissions, err := client.UserEffectivePermissions(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Permission{
+		{Action: "dashboards:read", Scope: "dashboards:*"},
+		{Action: "reports:read", Scope: "reports:*"},
+	}
+	if !reflect.DeepEqual(permissions, expected) {
+		t.Errorf("Unexpected permissions %v", permissions)
+	}
+}
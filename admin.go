@@ -3,15 +3,39 @@ package gapi
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
+	"time"
 )
 
+// UserAuthToken is a login session of a user.
+type UserAuthToken struct {
+	Id             int64     `json:"id"`
+	IsActive       bool      `json:"isActive"`
+	ClientIp       string    `json:"clientIp"`
+	Browser        string    `json:"browser"`
+	BrowserVersion string    `json:"browserVersion"`
+	Os             string    `json:"os"`
+	OsVersion      string    `json:"osVersion"`
+	Device         string    `json:"device"`
+	CreatedAt      time.Time `json:"createdAt"`
+	SeenAt         time.Time `json:"seenAt"`
+}
+
+// Quota is the limit and usage of a resource for a user or an org. A limit of
+// -1 means unlimited.
+type Quota struct {
+	OrgId  int64  `json:"org_id,omitempty"`
+	UserId int64  `json:"user_id,omitempty"`
+	Target string `json:"target"`
+	Limit  int64  `json:"limit"`
+	Used   int64  `json:"used"`
+}
+
 func (c *Client) CreateUser(user User) (int64, error) {
 	id := int64(0)
 	data, err := json.Marshal(user)
-	req, err := c.newRequest("POST", "/api/admin/users", nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("CreateUser", "POST", "/api/admin/users", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return id, err
 	}
@@ -20,7 +44,10 @@ func (c *Client) CreateUser(user User) (int64, error) {
 		return id, err
 	}
 	if resp.StatusCode != 200 {
-		return id, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return id, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	data, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -37,7 +64,7 @@ func (c *Client) CreateUser(user User) (int64, error) {
 }
 
 func (c *Client) DeleteUser(id int64) error {
-	req, err := c.newRequest("DELETE", fmt.Sprintf("/api/admin/users/%d", id), nil, nil)
+	req, err := c.newRequest("DeleteUser", "DELETE", fmt.Sprintf("/api/admin/users/%d", id), nil, nil)
 	if err != nil {
 		return err
 	}
@@ -46,7 +73,57 @@ func (c *Client) DeleteUser(id int64) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	return err
 }
+
+func (c *Client) UpdateUserPassword(id int64, password string) error {
+	body := map[string]string{
+		"password": password,
+	}
+	return c.sendJSON("UpdateUserPassword", "PUT", fmt.Sprintf("/api/admin/users/%d/password", id), nil, body, nil)
+}
+
+// UpdateUserPermissions grants or revokes Grafana server admin permission.
+func (c *Client) UpdateUserPermissions(id int64, isGrafanaAdmin bool) error {
+	body := map[string]bool{
+		"isGrafanaAdmin": isGrafanaAdmin,
+	}
+	return c.sendJSON("UpdateUserPermissions", "PUT", fmt.Sprintf("/api/admin/users/%d/permissions", id), nil, body, nil)
+}
+
+func (c *Client) DisableUser(id int64) error {
+	return c.sendJSON("DisableUser", "POST", fmt.Sprintf("/api/admin/users/%d/disable", id), nil, nil, nil)
+}
+
+func (c *Client) EnableUser(id int64) error {
+	return c.sendJSON("EnableUser", "POST", fmt.Sprintf("/api/admin/users/%d/enable", id), nil, nil, nil)
+}
+
+// LogoutUser revokes every session of the user on every device.
+func (c *Client) LogoutUser(id int64) error {
+	return c.sendJSON("LogoutUser", "POST", fmt.Sprintf("/api/admin/users/%d/logout", id), nil, nil, nil)
+}
+
+func (c *Client) RevokeUserAuthToken(id, tokenId int64) error {
+	body := map[string]int64{
+		"authTokenId": tokenId,
+	}
+	return c.sendJSON("RevokeUserAuthToken", "POST", fmt.Sprintf("/api/admin/users/%d/revoke-auth-token", id), nil, body, nil)
+}
+
+func (c *Client) UserAuthTokens(id int64) ([]UserAuthToken, error) {
+	tokens := make([]UserAuthToken, 0)
+	err := c.getJSON("UserAuthTokens", fmt.Sprintf("/api/admin/users/%d/auth-tokens", id), &tokens)
+	return tokens, err
+}
+
+func (c *Client) UserQuotas(id int64) ([]Quota, error) {
+	quotas := make([]Quota, 0)
+	err := c.getJSON("UserQuotas", fmt.Sprintf("/api/admin/users/%d/quotas", id), &quotas)
+	return quotas, err
+}
@@ -0,0 +1,170 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AdminSettings is the effective configuration of the Grafana server, keyed
+// by grafana.ini section. Secrets are masked by Grafana.
+type AdminSettings map[string]SettingsSection
+
+// SettingsSection holds the settings of a single grafana.ini section.
+type SettingsSection map[string]string
+
+// Get returns the value of key in section, or "" when it is not set.
+func (s AdminSettings) Get(section, key string) string {
+	return s[section][key]
+}
+
+// Bool parses the value of key as a boolean.
+func (s SettingsSection) Bool(key string) (bool, error) {
+	return strconv.ParseBool(s[key])
+}
+
+// Int parses the value of key as an integer.
+func (s SettingsSection) Int(key string) (int64, error) {
+	return strconv.ParseInt(s[key], 10, 64)
+}
+
+// AdminStats are the server wide usage counters of Grafana.
+type AdminStats struct {
+	Orgs             int64 `json:"orgs"`
+	Dashboards       int64 `json:"dashboards"`
+	Snapshots        int64 `json:"snapshots"`
+	Tags             int64 `json:"tags"`
+	Datasources      int64 `json:"datasources"`
+	Playlists        int64 `json:"playlists"`
+	Stars            int64 `json:"stars"`
+	Alerts           int64 `json:"alerts"`
+	Users            int64 `json:"users"`
+	Admins           int64 `json:"admins"`
+	Editors          int64 `json:"editors"`
+	Viewers          int64 `json:"viewers"`
+	ActiveUsers      int64 `json:"activeUsers"`
+	ActiveAdmins     int64 `json:"activeAdmins"`
+	ActiveEditors    int64 `json:"activeEditors"`
+	ActiveViewers    int64 `json:"activeViewers"`
+	ActiveSessions   int64 `json:"activeSessions"`
+	DailyActiveUsers int64 `json:"dailyActiveUsers"`
+	Annotations      int64 `json:"annotations"`
+}
+
+// Inventory is a snapshot of the content of a Grafana server.
+type Inventory struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Stats       *AdminStats    `json:"stats"`
+	Settings    AdminSettings  `json:"settings"`
+	Orgs        []OrgInventory `json:"orgs"`
+}
+
+// OrgInventory is the part of an Inventory about a single organization.
+type OrgInventory struct {
+	Org         Org `json:"org"`
+	DataSources int `json:"datasources"`
+	// Error is set, and DataSources zero, when the content of the org could
+	// not be read, e.g. because the user is not a member of it.
+	Error string `json:"error,omitempty"`
+}
+
+func (c *Client) AdminSettings() (AdminSettings, error) {
+	req, err := c.newRequest("AdminSettings", "GET", "/api/admin/settings", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := AdminSettings{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func (c *Client) AdminStats() (*AdminStats, error) {
+	req, err := c.newRequest("AdminStats", "GET", "/api/admin/stats", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AdminStats{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// Inventory collects the server stats and settings together with every
+// organization and the number of datasources it has. It requires Grafana
+// server admin permission. Orgs the user cannot read (401 or 403) are listed
+// with an Error rather than failing the whole inventory.
+func (c *Client) Inventory() (*Inventory, error) {
+	stats, err := c.AdminStats()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get stats")
+	}
+	settings, err := c.AdminSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get settings")
+	}
+	orgs, err := c.Orgs()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list orgs")
+	}
+
+	inventory := &Inventory{
+		GeneratedAt: time.Now().UTC(),
+		Stats:       stats,
+		Settings:    settings,
+		Orgs:        make([]OrgInventory, 0, len(orgs)),
+	}
+	for _, org := range orgs {
+		datasources, err := c.WithOrgID(org.Id).DataSources()
+		if isGrafanaStatus(err, 401) || isGrafanaStatus(err, 403) {
+			inventory.Orgs = append(inventory.Orgs, OrgInventory{Org: org, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list datasources of org %d", org.Id)
+		}
+		inventory.Orgs = append(inventory.Orgs, OrgInventory{
+			Org:         org,
+			DataSources: len(datasources),
+		})
+	}
+	return inventory, nil
+}
@@ -0,0 +1,90 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getAdminSettingsJSON = `{"DEFAULT":{"app_mode":"production"},"analytics":{"reporting_enabled":"true"},"users":{"allow_sign_up":"false"},"database":{"password":"************","type":"sqlite3"}}`
+	getAdminStatsJSON    = `{"orgs":2,"dashboards":4,"snapshots":2,"tags":6,"datasources":3,"playlists":1,"stars":1,"alerts":5,"users":3,"admins":1,"editors":0,"viewers":2,"activeUsers":1}`
+)
+
+func TestAdminSettings(t *testing.T) {
+	server, client := gapiTestTools(200, getAdminSettingsJSON)
+	defer server.Close()
+
+	settings, err := client.AdminSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(settings))
+
+	if settings.Get("database", "type") != "sqlite3" {
+		t.Error("Not correctly parsing returned settings.")
+	}
+	if enabled, err := settings["analytics"].Bool("reporting_enabled"); err != nil || !enabled {
+		t.Error("Not correctly parsing boolean setting.")
+	}
+}
+
+func TestAdminStats(t *testing.T) {
+	server, client := gapiTestTools(200, getAdminStatsJSON)
+	defer server.Close()
+
+	stats, err := client.AdminStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Orgs != 2 || stats.Dashboards != 4 || stats.Alerts != 5 || stats.Viewers != 2 {
+		t.Error("Not correctly parsing returned stats.")
+	}
+}
+
+func TestInventory(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/admin/stats":
+			fmt.Fprint(w, getAdminStatsJSON)
+		case "/api/admin/settings":
+			fmt.Fprint(w, getAdminSettingsJSON)
+		case "/api/orgs":
+			fmt.Fprint(w, `[{"id":1,"name":"Main Org."},{"id":2,"name":"Test Org."},{"id":3,"name":"Other Org."}]`)
+		case "/api/datasources":
+			switch r.Header.Get("X-Grafana-Org-Id") {
+			case "1":
+				fmt.Fprint(w, `[{"id":1,"name":"a"},{"id":2,"name":"b"}]`)
+			case "2":
+				fmt.Fprint(w, `[{"id":3,"name":"c"}]`)
+			default:
+				w.WriteHeader(401)
+				fmt.Fprint(w, `{"message":"User not a member of organization"}`)
+			}
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	inventory, err := client.Inventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(inventory))
+
+	if len(inventory.Orgs) != 3 || inventory.Stats.Datasources != 3 {
+		t.Fatal("Not correctly building inventory.")
+	}
+	if inventory.Orgs[0].DataSources != 2 || inventory.Orgs[1].DataSources != 1 {
+		t.Error("Datasources should be counted per org.")
+	}
+	if inventory.Orgs[2].Error == "" || inventory.Orgs[0].Error != "" {
+		t.Error("Orgs that cannot be read should be recorded with an error.")
+	}
+}
@@ -2,11 +2,16 @@ package gapi
 
 import (
 	"testing"
+
+	"github.com/gobs/pretty"
 )
 
 const (
 	createUserJSON = `{"id":1,"message":"User created"}`
 	deleteUserJSON = `{"message":"User deleted"}`
+
+	getUserAuthTokensJSON = `[{"id":361,"isActive":true,"clientIp":"127.0.0.1","browser":"Chrome","browserVersion":"72.0","os":"Linux","osVersion":"","device":"Other","createdAt":"2019-03-05T21:22:54+01:00","seenAt":"2019-03-06T19:41:06+01:00"}]`
+	getUserQuotasJSON     = `[{"user_id":1,"target":"org_user","limit":10,"used":1}]`
 )
 
 func TestCreateUser(t *testing.T) {
@@ -37,3 +42,83 @@ func TestDeleteUser(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestUpdateUserPassword(t *testing.T) {
+	server, client := gapiTestTools(200, `{"message":"User password updated"}`)
+	defer server.Close()
+
+	err := client.UpdateUserPassword(int64(1), "new-password")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateUserPermissions(t *testing.T) {
+	server, client := gapiTestTools(200, `{"message":"User permissions updated"}`)
+	defer server.Close()
+
+	err := client.UpdateUserPermissions(int64(1), false)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDisableUser(t *testing.T) {
+	server, client := gapiTestTools(200, `{"message":"User disabled"}`)
+	defer server.Close()
+
+	err := client.DisableUser(int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLogoutUserError(t *testing.T) {
+	server, client := gapiTestTools(404, `{"message":"User not found"}`)
+	defer server.Close()
+
+	err := client.LogoutUser(int64(1))
+	if gerr, ok := err.(*GrafanaError); !ok || gerr.StatusCode != 404 {
+		t.Errorf("Expected a 404 GrafanaError, got %v", err)
+	}
+}
+
+func TestUserAuthTokens(t *testing.T) {
+	server, client := gapiTestTools(200, getUserAuthTokensJSON)
+	defer server.Close()
+
+	tokens, err := client.UserAuthTokens(int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(pretty.PrettyFormat(tokens))
+
+	if len(tokens) != 1 || tokens[0].Id != 361 || tokens[0].ClientIp != "127.0.0.1" {
+		t.Error("Not correctly parsing returned auth tokens.")
+	}
+}
+
+func TestRevokeUserAuthToken(t *testing.T) {
+	server, client := gapiTestTools(200, `{"message":"User auth token revoked"}`)
+	defer server.Close()
+
+	err := client.RevokeUserAuthToken(int64(1), int64(361))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUserQuotas(t *testing.T) {
+	server, client := gapiTestTools(200, getUserQuotasJSON)
+	defer server.Close()
+
+	quotas, err := client.UserQuotas(int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(quotas) != 1 || quotas[0] != (Quota{UserId: 1, Target: "org_user", Limit: 10, Used: 1}) {
+		t.Error("Not correctly parsing returned quotas.")
+	}
+}
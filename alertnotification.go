@@ -18,7 +18,7 @@ type AlertNotification struct {
 
 func (c *Client) AlertNotification(id int64) (*AlertNotification, error) {
 	path := fmt.Sprintf("/api/alert-notifications/%d", id)
-	req, err := c.newRequest("GET", path, nil, nil)
+	req, err := c.newRequest("AlertNotification", "GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +46,7 @@ func (c *Client) NewAlertNotification(a *AlertNotification) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	req, err := c.newRequest("POST", "/api/alert-notifications", nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("NewAlertNotification", "POST", "/api/alert-notifications", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return 0, err
 	}
@@ -77,7 +77,7 @@ func (c *Client) UpdateAlertNotification(a *AlertNotification) error {
 	if err != nil {
 		return err
 	}
-	req, err := c.newRequest("PUT", path, nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("UpdateAlertNotification", "PUT", path, nil, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -95,7 +95,7 @@ func (c *Client) UpdateAlertNotification(a *AlertNotification) error {
 
 func (c *Client) DeleteAlertNotification(id int64) error {
 	path := fmt.Sprintf("/api/alert-notifications/%d", id)
-	req, err := c.newRequest("DELETE", path, nil, nil)
+	req, err := c.newRequest("DeleteAlertNotification", "DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,288 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEvent describes a write made through a client.
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+	// Operation is the Client method that made the write, e.g.
+	// "SaveDashboard".
+	Operation string `json:"operation,omitempty"`
+	Method    string `json:"method"`
+	// Path is the path of the request relative to the base URL of the
+	// client.
+	Path string `json:"path"`
+	// Kind is the kind of resource changed, e.g. "dashboard" or "org-user".
+	Kind string `json:"kind,omitempty"`
+	// ID identifies the resource changed, from the path of the request or
+	// else the uid or id of the response, e.g. for resources it created.
+	ID string `json:"id,omitempty"`
+	// OrgID is the organization in the path of /api/orgs requests, else the
+	// one set with WithOrgID, zero for the current org of the user.
+	OrgID int64 `json:"orgId,omitempty"`
+	// Actor is the user of the basic auth credentials of the client, empty
+	// for API keys and tokens.
+	Actor string `json:"actor,omitempty"`
+	// Before is the resource as last read through the cache of the client,
+	// if it is enabled and holds it. Secrets are redacted.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After is the body of the request. Secrets are redacted.
+	After json.RawMessage `json:"after,omitempty"`
+	// Success reports whether the server accepted the write with a 2xx.
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Latency is the time the write took, in nanoseconds in JSON.
+	Latency time.Duration `json:"latency"`
+}
+
+// AuditHook is told about every write made through a client, once it is done.
+// It is called synchronously and must be safe for concurrent use.
+type AuditHook interface {
+	Audit(event AuditEvent)
+}
+
+// AuditHookFunc adapts a function to an AuditHook.
+type AuditHookFunc func(event AuditEvent)
+
+func (f AuditHookFunc) Audit(event AuditEvent) {
+	f(event)
+}
+
+// SetAuditHook has the hook told about the writes of the client, and of the
+// copies of it made afterwards with WithOrgID. Writes planned in dry-run mode
+// are not audited. A nil hook stops auditing.
+func (c *Client) SetAuditHook(hook AuditHook) {
+	c.auditHook = hook
+}
+
+// auditKinds maps the routes of writes to the kind of resource they change.
+// Routes are matched by prefix, in order.
+var auditKinds = []struct {
+	prefix, kind string
+}{
+	{"/api/dashboards", "dashboard"},
+	{"/api/folders", "folder"},
+	{"/api/datasources/:id/permissions", "datasource-permission"},
+	{"/api/datasources", "datasource"},
+	{"/api/alert-notifications", "alert-notification"},
+	{"/api/orgs/:id/users", "org-user"},
+	{"/api/org/users", "org-user"},
+	{"/api/org/invites", "org-invite"},
+	{"/api/user/invite", "org-invite"},
+	{"/api/orgs/:id/quotas", "org-quota"},
+	{"/api/orgs", "org"},
+	{"/api/org", "org"},
+	{"/api/admin/users/:id/quotas", "user-quota"},
+	{"/api/admin/users", "user"},
+	{"/api/users", "user"},
+	{"/api/user", "user"},
+	{"/api/access-control/roles", "role"},
+	{"/api/access-control", "role-assignment"},
+}
+
+func auditKind(route string) string {
+	for _, k := range auditKinds {
+		if underAny(route, []string{k.prefix}) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// auditOrgID returns the org in the path of /api/orgs/:id routes, else the
+// org set with WithOrgID.
+func auditOrgID(op Operation) int64 {
+	if underAny(op.Route, []string{"/api/orgs/:id"}) {
+		if id, err := strconv.ParseInt(op.Params["id"], 10, 64); err == nil {
+			return id
+		}
+	}
+	return op.OrgID
+}
+
+// auditID returns the user added by a request adding a user to an org, else
+// the last parameter of the route identifying a resource, else the uid or id
+// of the response body.
+func auditID(op Operation, request, body []byte) string {
+	if op.Route == "/api/orgs/:id/users" {
+		added := struct {
+			LoginOrEmail string `json:"loginOrEmail"`
+		}{}
+		json.Unmarshal(request, &added)
+		return added.LoginOrEmail
+	}
+	segments := strings.Split(op.Route, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if name := strings.TrimPrefix(segments[i], ":"); name != segments[i] && name != "action" {
+			return op.Params[name]
+		}
+	}
+	created := struct {
+		Uid   string      `json:"uid"`
+		Id    json.Number `json:"id"`
+		OrgId json.Number `json:"orgId"`
+	}{}
+	json.Unmarshal(body, &created)
+	switch {
+	case created.Uid != "":
+		return created.Uid
+	case created.Id != "":
+		return created.Id.String()
+	}
+	return created.OrgId.String()
+}
+
+// redactedRaw returns data with its secrets redacted, or nil when it is not
+// JSON.
+func redactedRaw(data []byte) json.RawMessage {
+	redacted, err := RedactJSON(data)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func (c *Client) audit(req *http.Request, op Operation, send RoundTripFunc) (*http.Response, error) {
+	event := AuditEvent{
+		Time:      time.Now(),
+		Operation: op.Name,
+		Method:    req.Method,
+		Path:      op.path(),
+		Kind:      auditKind(op.Route),
+		OrgID:     auditOrgID(op),
+		Actor:     c.baseURL.User.Username(),
+	}
+	if c.cache != nil {
+		if before := c.cache.cached(op.OrgID, writtenPaths(req, op, nil)); before != nil {
+			event.Before = redactedRaw(before)
+		}
+	}
+	var request []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			request, _ = ioutil.ReadAll(body)
+			event.After = redactedRaw(request)
+		}
+	}
+
+	resp, err := send(req)
+	event.Latency = time.Since(event.Time)
+
+	var body []byte
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		event.StatusCode = resp.StatusCode
+		event.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			event.Error = err.Error()
+		} else if !event.Success {
+			var gmsg GrafanaErrorMessage
+			json.Unmarshal(body, &gmsg)
+			event.Error = fmt.Sprint(gmsg)
+		}
+	}
+	if event.Success {
+		event.ID = auditID(op, request, body)
+	} else {
+		event.ID = auditID(op, request, nil)
+	}
+
+	c.auditHook.Audit(event)
+	return resp, err
+}
+
+// JSONLinesAuditSink writes audit events as JSON, one per line.
+type JSONLinesAuditSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	err    error
+}
+
+// NewJSONLinesAuditSink returns a sink writing to w.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// OpenJSONLinesAuditSink returns a sink appending to the file at path, created
+// if needed. Close it when done.
+func OpenJSONLinesAuditSink(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesAuditSink{w: f, closer: f}, nil
+}
+
+// Audit writes the event. Failures are reported by Err, as the write it
+// describes is already done.
+func (s *JSONLinesAuditSink) Audit(event AuditEvent) {
+	line, err := json.Marshal(event)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		_, err = s.w.Write(append(line, '\n'))
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the first error met writing events, if any.
+func (s *JSONLinesAuditSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close closes the file opened by OpenJSONLinesAuditSink. It does nothing for
+// sinks made by NewJSONLinesAuditSink.
+func (s *JSONLinesAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// MemoryAuditSink keeps audit events in memory, e.g. for tests.
+type MemoryAuditSink struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (s *MemoryAuditSink) Audit(event AuditEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+// Events returns the events audited so far, oldest first.
+func (s *MemoryAuditSink) Events() []AuditEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AuditEvent(nil), s.events...)
+}
+
+// Reset forgets the events audited so far.
+func (s *MemoryAuditSink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = nil
+}
@@ -0,0 +1,154 @@
+package gapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			fmt.Fprint(w, `{"meta":{"version":1},"dashboard":{"uid":"abc","title":"Ops"}}`)
+		case r.URL.Path == "/api/dashboards/db":
+			fmt.Fprint(w, `{"id":1,"uid":"abc","status":"success","version":2}`)
+		case r.URL.Path == "/api/datasources":
+			fmt.Fprint(w, `{"id":7,"message":"Datasource added"}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Folder not found"}`)
+		}
+	}))
+	defer server.Close()
+	client.baseURL.User = url.UserPassword("automation", "secret")
+	client.EnableCache(CacheConfig{TTL: time.Minute})
+	sink := &MemoryAuditSink{}
+	client.SetAuditHook(sink)
+
+	dashboard, err := client.GetDashboardByUID("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboard.Model["title"] = "Ops 2"
+	if _, err := client.SaveDashboard(&DashboardSaveOpts{Model: dashboard.Model}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.WithOrgID(2).NewDataSource(&DataSource{Name: "prom", BasicAuthPassword: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteFolderByUID("missing"); err == nil {
+		t.Fatal("Expected an error deleting a missing folder")
+	}
+	dryRun, _ := client.DryRun()
+	dryRun.DeleteFolderByUID("f")
+
+	events := sink.Events()
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	save, create, remove := events[0], events[1], events[2]
+	if save.Operation != "SaveDashboard" || save.Kind != "dashboard" || save.ID != "abc" ||
+		save.Actor != "automation" || !save.Success || save.StatusCode != 200 || save.Latency <= 0 {
+		t.Errorf("Unexpected event %+v", save)
+	}
+	if !strings.Contains(string(save.Before), `"title":"Ops"`) || !strings.Contains(string(save.After), `"title":"Ops 2"`) {
+		t.Errorf("Expected the dashboard before and after, got %s and %s", save.Before, save.After)
+	}
+	if create.Kind != "datasource" || create.ID != "7" || create.OrgID != 2 || create.Before != nil {
+		t.Errorf("Unexpected event %+v", create)
+	}
+	if strings.Contains(string(create.After), "hunter2") || !strings.Contains(string(create.After), Redacted) {
+		t.Errorf("Secrets should be redacted, got %s", create.After)
+	}
+	if remove.Operation != "DeleteFolderByUID" || remove.Kind != "folder" || remove.ID != "missing" ||
+		remove.Success || remove.StatusCode != 404 || !strings.Contains(remove.Error, "Folder not found") {
+		t.Errorf("Unexpected event %+v", remove)
+	}
+
+	sink.Reset()
+	if len(sink.Events()) != 0 {
+		t.Errorf("Reset should forget events")
+	}
+}
+
+func TestAuditOrgRoutes(t *testing.T) {
+	server, client := gapiTestTools(200, `{"message":"User added to organization","userId":9}`)
+	defer server.Close()
+	sink := &MemoryAuditSink{}
+	client.SetAuditHook(sink)
+
+	if err := client.WithOrgID(2).AddOrgUser(3, "jane@example.com", RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UpdateOrg(4, "Ops"); err != nil {
+		t.Fatal(err)
+	}
+
+	events := sink.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+	if add := events[0]; add.Kind != "org-user" || add.ID != "jane@example.com" || add.OrgID != 3 {
+		t.Errorf("Unexpected event %+v", add)
+	}
+	if update := events[1]; update.Kind != "org" || update.ID != "4" || update.OrgID != 4 {
+		t.Errorf("Unexpected event %+v", update)
+	}
+}
+
+func TestJSONLinesAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLinesAuditSink(&buf)
+	sink.Audit(AuditEvent{Operation: "SaveDashboard", Kind: "dashboard", ID: "abc", Success: true})
+	sink.Audit(AuditEvent{Operation: "DeleteFolderByUID", Kind: "folder", ID: "f", After: json.RawMessage(`{"a":1}`)})
+	if sink.Err() != nil {
+		t.Fatal(sink.Err())
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if strings.Join(ids, ",") != "abc,f" {
+		t.Errorf("Expected one line per event, got %v", ids)
+	}
+
+	dir, err := ioutil.TempDir("", "gapi-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.jsonl")
+	for i := 0; i < 2; i++ {
+		sink, err := OpenJSONLinesAuditSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sink.Audit(AuditEvent{ID: fmt.Sprint(i)})
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Expected the file to be appended to, got %q", data)
+	}
+}
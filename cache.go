@@ -0,0 +1,337 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheConfig configures the response cache of a client.
+type CacheConfig struct {
+	// TTL is how long a response is reused without asking the server. Once
+	// it expires, responses with an ETag or a Last-Modified header are
+	// revalidated with a conditional request, and others fetched again. Zero
+	// revalidates on every read.
+	TTL time.Duration
+	// MaxEntries caps the number of responses kept. Zero means no cap.
+	MaxEntries int
+}
+
+// CacheStats counts the reads of a client served by its cache.
+type CacheStats struct {
+	// Hits were served without a request.
+	Hits int64
+	// Revalidated were served after the server answered 304 Not Modified.
+	Revalidated int64
+	// Misses were fetched from the server.
+	Misses int64
+	// Shared waited for an identical read in flight and got its response.
+	Shared int64
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	path    string
+	expires time.Time
+}
+
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.status, http.StatusText(r.status)),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+func (r *cachedResponse) validated() bool {
+	return r.header.Get("ETag") != "" || r.header.Get("Last-Modified") != ""
+}
+
+type responseCache struct {
+	config CacheConfig
+	flight singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	stats   CacheStats
+	// generation counts invalidations. A read is only cached if none
+	// happened while it was in flight, as it may predate the write.
+	generation uint64
+}
+
+// cacheKey identifies a read by the org it is made in, its path and its
+// query. Query parameters are sorted by url.Values.Encode.
+func cacheKey(req *http.Request, op Operation) string {
+	return fmt.Sprintf("%d %s?%s", op.OrgID, op.path(), req.URL.Query().Encode())
+}
+
+// do serves GET requests from the cache, and invalidates the cached reads
+// writes may make stale. Reads that are not GETs, e.g. data source queries,
+// are neither cached nor invalidate anything.
+func (rc *responseCache) do(req *http.Request, op Operation, send RoundTripFunc) (*http.Response, error) {
+	if req.Method != "GET" {
+		resp, err := send(req)
+		if op.mutates() {
+			rc.invalidate(writtenPaths(req, op, resp))
+		}
+		return resp, err
+	}
+
+	key := cacheKey(req, op)
+	rc.mu.Lock()
+	entry := rc.entries[key]
+	if entry != nil && time.Now().Before(entry.expires) {
+		rc.stats.Hits++
+		rc.mu.Unlock()
+		return entry.response(req), nil
+	}
+	rc.mu.Unlock()
+
+	leader := false
+	v, err, _ := rc.flight.Do(key, func() (interface{}, error) {
+		leader = true
+		return rc.fetch(req, op, key, entry, send)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !leader {
+		rc.mu.Lock()
+		rc.stats.Shared++
+		rc.mu.Unlock()
+	}
+	return v.(*cachedResponse).response(req), nil
+}
+
+// fetch sends a read, conditional when the stale entry has validators, and
+// caches successful responses.
+func (rc *responseCache) fetch(req *http.Request, op Operation, key string, stale *cachedResponse, send RoundTripFunc) (*cachedResponse, error) {
+	if stale != nil && stale.validated() {
+		req = req.Clone(req.Context())
+		if etag := stale.header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := stale.header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	rc.mu.Lock()
+	generation := rc.generation
+	rc.mu.Unlock()
+
+	resp, err := send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	current := rc.generation == generation
+	expires := time.Now().Add(rc.config.TTL)
+	if resp.StatusCode == http.StatusNotModified && stale != nil {
+		rc.stats.Revalidated++
+		renewed := *stale
+		renewed.expires = expires
+		if current && rc.entries[key] == stale {
+			rc.entries[key] = &renewed
+		}
+		return &renewed, nil
+	}
+
+	rc.stats.Misses++
+	entry := &cachedResponse{
+		status:  resp.StatusCode,
+		header:  resp.Header,
+		body:    body,
+		path:    op.path(),
+		expires: expires,
+	}
+	if current && resp.StatusCode == 200 && (rc.config.TTL > 0 || entry.validated()) {
+		rc.store(key, entry)
+	}
+	return entry, nil
+}
+
+// cached returns the body of the first cached read of paths in an org, if
+// any, whether or not it expired.
+func (rc *responseCache) cached(orgID int64, paths []string) []byte {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, p := range paths {
+		if entry := rc.entries[fmt.Sprintf("%d %s?", orgID, p)]; entry != nil {
+			return entry.body
+		}
+	}
+	return nil
+}
+
+// store adds an entry, making room for it by evicting expired entries, then
+// the one expiring first.
+func (rc *responseCache) store(key string, entry *cachedResponse) {
+	if _, ok := rc.entries[key]; !ok && rc.config.MaxEntries > 0 && len(rc.entries) >= rc.config.MaxEntries {
+		now := time.Now()
+		oldest := ""
+		for k, e := range rc.entries {
+			if now.After(e.expires) {
+				delete(rc.entries, k)
+			} else if oldest == "" || e.expires.Before(rc.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(rc.entries) >= rc.config.MaxEntries {
+			delete(rc.entries, oldest)
+		}
+	}
+	rc.entries[key] = entry
+}
+
+// aliasedPrefixes groups the paths under which a resource is reachable by
+// several identifiers, e.g. a data source by id, uid or name. A write to one
+// of them invalidates every cached read of the group.
+var aliasedPrefixes = [][]string{
+	{"/api/datasources"},
+	{"/api/folders"},
+	{"/api/orgs", "/api/org"},
+	{"/api/users", "/api/user", "/api/admin/users"},
+}
+
+// cascades maps paths to the paths of the resources writes under them may
+// change too, e.g. deleting a folder deletes its dashboards, and deleting an
+// org everything in it.
+var cascades = map[string][]string{
+	"/api/folders": {"/api/dashboards"},
+	"/api/orgs":    {"/api"},
+}
+
+// writtenPaths returns the paths of the resources a write changes. Saving a
+// dashboard changes the dashboard with the uid of the response, or of the
+// request when it failed.
+func writtenPaths(req *http.Request, op Operation, resp *http.Response) []string {
+	paths := []string{op.path()}
+	if op.Route != "/api/dashboards/db" {
+		return paths
+	}
+
+	saved := struct {
+		Uid       string `json:"uid"`
+		Dashboard struct {
+			Uid string `json:"uid"`
+		} `json:"dashboard"`
+	}{}
+	if resp != nil && resp.StatusCode == 200 {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err == nil {
+			json.Unmarshal(body, &saved)
+		}
+	}
+	if saved.Uid == "" && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			json.NewDecoder(body).Decode(&saved)
+			saved.Uid = saved.Dashboard.Uid
+		}
+	}
+	if saved.Uid != "" {
+		paths = append(paths, "/api/dashboards/uid/"+saved.Uid)
+	}
+	return paths
+}
+
+// stale reports whether a write to the resource at written makes a cached
+// read of cached stale: reads of the resource, of its sub-resources, of the
+// lists it is part of, searches, and reads of aliased and cascaded resources.
+func stale(written, cached string) bool {
+	if cached == written || strings.HasPrefix(cached, written+"/") ||
+		strings.HasPrefix(written, cached+"/") || cached == "/api/search" {
+		return true
+	}
+	for _, group := range aliasedPrefixes {
+		if underAny(written, group) && underAny(cached, group) {
+			return true
+		}
+	}
+	for prefix, changed := range cascades {
+		if underAny(written, []string{prefix}) && underAny(cached, changed) {
+			return true
+		}
+	}
+	return false
+}
+
+func underAny(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidate drops the cached reads that writes to paths make stale, in every
+// org. Switching the current org of the user drops every cached read.
+func (rc *responseCache) invalidate(paths []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.generation++
+	for key, entry := range rc.entries {
+		for _, written := range paths {
+			if strings.HasPrefix(written, "/api/user/using/") || stale(written, entry.path) {
+				delete(rc.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// EnableCache caches the successful GET responses of the client, and of the
+// copies of it made afterwards with WithOrgID, keyed by org, path and query.
+// Concurrent identical reads share a single request. Writes made through the
+// client invalidate the cached reads of the resources they change; writes
+// made by others are seen once the TTL expires.
+func (c *Client) EnableCache(cfg CacheConfig) {
+	c.cache = &responseCache{config: cfg, entries: map[string]*cachedResponse{}}
+}
+
+// InvalidateCache drops every response cached by the client.
+func (c *Client) InvalidateCache() {
+	if c.cache == nil {
+		return
+	}
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	c.cache.generation++
+	c.cache.entries = map[string]*cachedResponse{}
+}
+
+// CacheStats returns how the reads of the client were served since
+// EnableCache was called.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	return c.cache.stats
+}
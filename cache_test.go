@@ -0,0 +1,210 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingServer(requests map[string]int, mu *sync.Mutex, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path+" "+r.Header.Get("X-Grafana-Org-Id")]++
+		mu.Unlock()
+		handler(w, r)
+	})
+}
+
+func TestCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server, client := gapiTestToolsWithHandler(countingServer(requests, &mu, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			fmt.Fprint(w, `{"id":1,"uid":"abc","status":"success","version":2}`)
+			return
+		}
+		uid := strings.TrimPrefix(r.URL.Path, "/api/dashboards/uid/")
+		fmt.Fprintf(w, `{"meta":{"slug":"%s"},"dashboard":{"uid":"%s"}}`, uid, uid)
+	}))
+	defer server.Close()
+	client.EnableCache(CacheConfig{TTL: time.Minute})
+	scoped := client.WithOrgID(2)
+
+	for i := 0; i < 3; i++ {
+		for _, c := range []*Client{client, scoped} {
+			for _, uid := range []string{"abc", "def"} {
+				dashboard, err := c.GetDashboardByUID(uid)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if dashboard.Model["uid"] != uid {
+					t.Errorf("Expected dashboard %s, got %v", uid, dashboard.Model)
+				}
+			}
+		}
+	}
+	for _, key := range []string{"GET /api/dashboards/uid/abc ", "GET /api/dashboards/uid/def ", "GET /api/dashboards/uid/abc 2"} {
+		if requests[key] != 1 {
+			t.Errorf("Expected a single %q request, got %d", key, requests[key])
+		}
+	}
+	if stats := client.CacheStats(); stats.Hits != 8 || stats.Misses != 4 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	if _, err := client.SaveDashboard(&DashboardSaveOpts{Model: map[string]interface{}{"uid": "abc"}}); err != nil {
+		t.Fatal(err)
+	}
+	client.GetDashboardByUID("abc")
+	scoped.GetDashboardByUID("abc")
+	client.GetDashboardByUID("def")
+	if requests["GET /api/dashboards/uid/abc "] != 2 || requests["GET /api/dashboards/uid/abc 2"] != 2 {
+		t.Errorf("Saving a dashboard should invalidate it in every org, got %v", requests)
+	}
+	if requests["GET /api/dashboards/uid/def "] != 1 {
+		t.Errorf("Saving a dashboard should not invalidate other dashboards")
+	}
+
+	client.InvalidateCache()
+	client.GetDashboardByUID("def")
+	if requests["GET /api/dashboards/uid/def "] != 2 {
+		t.Errorf("InvalidateCache should drop every response")
+	}
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	notModified := int64(0)
+	server, client := gapiTestToolsWithHandler(countingServer(requests, &mu, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt64(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `[{"id":1,"uid":"f","title":"Ops"}]`)
+	}))
+	defer server.Close()
+	client.EnableCache(CacheConfig{})
+
+	for i := 0; i < 3; i++ {
+		folders, err := client.GetAllFolders()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(folders) != 1 || folders[0].Title != "Ops" {
+			t.Errorf("Unexpected folders %+v", folders)
+		}
+	}
+	if notModified != 2 {
+		t.Errorf("Expected 2 conditional requests, got %d", notModified)
+	}
+	if stats := client.CacheStats(); stats.Revalidated != 2 || stats.Misses != 1 || stats.Hits != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestCacheSharesConcurrentReads(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	requests := int64(0)
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"meta":{},"dashboard":{"uid":"abc"}}`)
+	}))
+	defer server.Close()
+	client.EnableCache(CacheConfig{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetDashboardByUID("abc"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("Concurrent identical reads should share a request, got %d", requests)
+	}
+	if stats := client.CacheStats(); stats.Shared != 4 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestCacheSkipsReadsOlderThanWrites(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	started, release := make(chan struct{}), make(chan struct{})
+	var reads int32
+	server, client := gapiTestToolsWithHandler(countingServer(requests, &mu, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && atomic.AddInt32(&reads, 1) == 1 {
+			close(started)
+			<-release
+		}
+		fmt.Fprint(w, `{"meta":{"slug":"abc"},"dashboard":{"uid":"abc"}}`)
+	}))
+	defer server.Close()
+	client.EnableCache(CacheConfig{TTL: time.Minute})
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GetDashboardByUID("abc")
+		done <- err
+	}()
+	<-started
+	if err := client.DeleteDashboardByUID("abc"); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetDashboardByUID("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if requests["GET /api/dashboards/uid/abc "] != 2 {
+		t.Errorf("A read in flight during a write should not be cached, got %d reads", requests["GET /api/dashboards/uid/abc "])
+	}
+}
+
+func TestCacheKeptByQueries(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server, client := gapiTestToolsWithHandler(countingServer(requests, &mu, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/ds/query" {
+			fmt.Fprint(w, `{"results":{}}`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+	client.EnableCache(CacheConfig{TTL: time.Minute})
+
+	var hits []interface{}
+	for i := 0; i < 2; i++ {
+		if err := client.getJSON("Search", "/api/search", &hits); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.QueryDataSources(QueryRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests["GET /api/search "] != 1 || requests["POST /api/ds/query "] != 2 {
+		t.Errorf("Queries should neither be cached nor invalidate searches, got %v", requests)
+	}
+}
@@ -2,19 +2,40 @@ package gapi
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 type Client struct {
 	key     string
 	baseURL url.URL
+	// orgID scopes requests to an organization through the
+	// X-Grafana-Org-Id header. Zero uses the current org of the user.
+	orgID int64
+	// version caches the version of the server, see Version.
+	version *versionCache
+	// limiter, if set, limits the rate and concurrency of requests.
+	limiter *requestLimiter
+	// middleware wraps the sending of requests, see Use.
+	middleware []Middleware
+	// cache, if set, caches responses, see EnableCache.
+	cache *responseCache
+	// plan, if set, receives the writes of the client instead of the
+	// server, see DryRun.
+	plan *Plan
+	// auditHook, if set, is told about every write, see SetAuditHook.
+	auditHook AuditHook
 	*http.Client
 }
 
@@ -33,13 +54,67 @@ func New(auth, baseURL string) (*Client, error) {
 		key = fmt.Sprintf("Bearer %s", auth)
 	}
 	return &Client{
-		key,
-		*u,
-		&http.Client{},
+		key:     key,
+		baseURL: *u,
+		version: &versionCache{},
+		Client:  &http.Client{},
 	}, nil
 }
 
-func (c *Client) newRequest(method, requestPath string, query url.Values, body io.Reader) (*http.Request, error) {
+// WithOrgID returns a copy of the client whose requests act on the
+// organization with the given id rather than the user's current one.
+func (c *Client) WithOrgID(orgID int64) *Client {
+	clone := *c
+	clone.orgID = orgID
+	return &clone
+}
+
+// Do sends a request built by newRequest. Every request of the client goes
+// through it.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	op := c.operation(req)
+	req = withOperation(req, op)
+	if c.plan != nil && op.mutates() {
+		if err := c.plan.add(req, op); err != nil {
+			return nil, err
+		}
+		return plannedResponse(req), nil
+	}
+	send := RoundTripFunc(c.Client.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		send = c.middleware[i](send)
+	}
+	if c.limiter != nil {
+		send = c.limit(send)
+	}
+	if c.cache != nil {
+		cache, next := c.cache, send
+		send = func(req *http.Request) (*http.Response, error) {
+			return cache.do(req, op, next)
+		}
+	}
+	if c.auditHook != nil && op.mutates() {
+		return c.audit(req, op, send)
+	}
+	return send(req)
+}
+
+// limit waits for the limiter before sending requests with next. It wraps the
+// middleware so that the time spent waiting is not part of what they measure.
+func (c *Client) limit(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		release, err := c.limiter.acquire(req)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return next(req)
+	}
+}
+
+// newRequest builds a request of the operation with the given name, the
+// Client method making it, see Operation.
+func (c *Client) newRequest(name, method, requestPath string, query url.Values, body io.Reader) (*http.Request, error) {
 	url := c.baseURL
 	url.Path = path.Join(url.Path, requestPath)
 	url.RawQuery = query.Encode()
@@ -50,6 +125,9 @@ func (c *Client) newRequest(method, requestPath string, query url.Values, body i
 	if c.key != "" {
 		req.Header.Add("Authorization", c.key)
 	}
+	if c.orgID != 0 {
+		req.Header.Add("X-Grafana-Org-Id", strconv.FormatInt(c.orgID, 10))
+	}
 
 	if os.Getenv("GF_LOG") != "" {
 		if body == nil {
@@ -60,5 +138,49 @@ func (c *Client) newRequest(method, requestPath string, query url.Values, body i
 	}
 
 	req.Header.Add("Content-Type", "application/json")
-	return req, err
+	return withOperationName(req, name), err
+}
+
+// getJSON decodes the response to a GET request into result.
+func (c *Client) getJSON(name, path string, result interface{}) error {
+	return c.sendJSON(name, "GET", path, nil, nil, result)
+}
+
+// sendJSON sends a request with body, unless nil, encoded as JSON and decodes
+// the response into result, unless nil. Responses other than 200 are returned
+// as a GrafanaError.
+func (c *Client) sendJSON(name, method, path string, query url.Values, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "Failed to marshall request JSON")
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+	req, err := c.newRequest(name, method, path, query, reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	if result == nil {
+		return nil
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
 }
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+// invocation is a command being run.
+type invocation struct {
+	app    *app
+	client *gapi.Client
+	args   []string
+}
+
+// arg returns the i-th argument, named for the error when it is missing.
+func (inv *invocation) arg(i int, name string) (string, error) {
+	if i >= len(inv.args) {
+		return "", fmt.Errorf("Missing %s", name)
+	}
+	return inv.args[i], nil
+}
+
+func (inv *invocation) intArg(i int, name string) (int64, error) {
+	s, err := inv.arg(i, name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s %q", name, s)
+	}
+	return n, nil
+}
+
+// readJSON decodes the JSON file at path, or the standard input for "-".
+func (inv *invocation) readJSON(path string, v interface{}) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(inv.app.stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("Invalid JSON in %s: %s", path, err)
+	}
+	return nil
+}
+
+type runFunc func(inv *invocation) (interface{}, error)
+
+// command is a subcommand, named after the resource and the action, e.g.
+// "dashboards get".
+type command struct {
+	resource, action string
+	// args describes the arguments, e.g. "<uid>".
+	args string
+	help string
+	// columns are the JSON fields of the result shown as a table.
+	columns []string
+	// setup declares the flags of the command and returns the function
+	// running it.
+	setup func(fs *flag.FlagSet) runFunc
+}
+
+var (
+	orgColumns               = []string{"id", "name"}
+	orgUserColumns           = []string{"userId", "login", "email", "role"}
+	dashboardColumns         = []string{"dashboard.uid", "dashboard.title", "meta.version", "meta.folderTitle"}
+	folderColumns            = []string{"id", "uid", "title", "parentUid"}
+	dataSourceColumns        = []string{"id", "uid", "name", "type", "url", "isDefault"}
+	userColumns              = []string{"id", "login", "email", "name", "isAdmin"}
+	userLookupColumns        = []string{"id", "login", "email", "name", "isGrafanaAdmin"}
+	alertNotificationColumns = []string{"id", "name", "type", "isDefault"}
+	idColumns                = []string{"id"}
+)
+
+func noFlags(run runFunc) func(fs *flag.FlagSet) runFunc {
+	return func(fs *flag.FlagSet) runFunc { return run }
+}
+
+var commands = []command{
+	{"orgs", "list", "", "List the organizations.", orgColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		return inv.client.Orgs()
+	})},
+
+	{"org-users", "list", "<org-id>", "List the members of an organization.", orgUserColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		orgID, err := inv.intArg(0, "org id")
+		if err != nil {
+			return nil, err
+		}
+		return inv.client.OrgUsers(orgID)
+	})},
+	{"org-users", "add", "[-role role] <org-id> <login-or-email>", "Add a user to an organization.", nil, func(fs *flag.FlagSet) runFunc {
+		role := fs.String("role", string(gapi.RoleViewer), "role of the user: Viewer, Editor or Admin")
+		return func(inv *invocation) (interface{}, error) {
+			orgID, err := inv.intArg(0, "org id")
+			if err != nil {
+				return nil, err
+			}
+			user, err := inv.arg(1, "login or email")
+			if err != nil {
+				return nil, err
+			}
+			return nil, inv.client.AddOrgUser(orgID, user, gapi.Role(*role))
+		}
+	}},
+	{"org-users", "update", "-role role <org-id> <user-id>", "Change the role of a member of an organization.", nil, func(fs *flag.FlagSet) runFunc {
+		role := fs.String("role", "", "role of the user: Viewer, Editor or Admin")
+		return func(inv *invocation) (interface{}, error) {
+			orgID, err := inv.intArg(0, "org id")
+			if err != nil {
+				return nil, err
+			}
+			userID, err := inv.intArg(1, "user id")
+			if err != nil {
+				return nil, err
+			}
+			return nil, inv.client.UpdateOrgUser(orgID, userID, gapi.Role(*role))
+		}
+	}},
+	{"org-users", "remove", "<org-id> <user-id>", "Remove a user from an organization.", nil, noFlags(func(inv *invocation) (interface{}, error) {
+		orgID, err := inv.intArg(0, "org id")
+		if err != nil {
+			return nil, err
+		}
+		userID, err := inv.intArg(1, "user id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, inv.client.RemoveOrgUser(orgID, userID)
+	})},
+
+	{"dashboards", "get", "<uid>", "Show a dashboard.", dashboardColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		uid, err := inv.arg(0, "uid")
+		if err != nil {
+			return nil, err
+		}
+		return inv.client.GetDashboardByUID(uid)
+	})},
+	{"dashboards", "save", "[-file path] [-folder-id id] [-overwrite]", "Create or update a dashboard from its JSON model, or from the output of dashboards get.", []string{"uid", "version", "status", "url"}, func(fs *flag.FlagSet) runFunc {
+		file := fs.String("file", "-", "JSON file of the dashboard, - for the standard input")
+		folderID := fs.Int("folder-id", 0, "id of the folder of the dashboard")
+		overwrite := fs.Bool("overwrite", false, "overwrite a dashboard with the same uid or title regardless of its version")
+		return func(inv *invocation) (interface{}, error) {
+			model := map[string]interface{}{}
+			if err := inv.readJSON(*file, &model); err != nil {
+				return nil, err
+			}
+			if wrapped, ok := model["dashboard"].(map[string]interface{}); ok {
+				model = wrapped
+			}
+			return inv.client.SaveDashboard(&gapi.DashboardSaveOpts{Model: model, FolderID: *folderID, Overwrite: *overwrite})
+		}
+	}},
+	{"dashboards", "delete", "<uid>", "Delete a dashboard.", nil, noFlags(func(inv *invocation) (interface{}, error) {
+		uid, err := inv.arg(0, "uid")
+		if err != nil {
+			return nil, err
+		}
+		return nil, inv.client.DeleteDashboardByUID(uid)
+	})},
+
+	{"folders", "list", "", "List the folders.", folderColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		return inv.client.GetAllFolders()
+	})},
+	{"folders", "create", "[-uid uid] [-parent-uid uid] <title>", "Create a folder.", folderColumns, func(fs *flag.FlagSet) runFunc {
+		uid := fs.String("uid", "", "uid of the folder, generated when empty")
+		parentUID := fs.String("parent-uid", "", "uid of the parent folder, for nested folders")
+		return func(inv *invocation) (interface{}, error) {
+			title, err := inv.arg(0, "title")
+			if err != nil {
+				return nil, err
+			}
+			return inv.client.CreateFolder(&gapi.FolderCreateOpts{Title: title, Uid: *uid, ParentUid: *parentUID})
+		}
+	}},
+
+	{"datasources", "get", "[-name] <id-or-uid>", "Show a data source by id or uid, or by name with -name.", dataSourceColumns, func(fs *flag.FlagSet) runFunc {
+		byName := fs.Bool("name", false, "look the data source up by name")
+		return func(inv *invocation) (interface{}, error) {
+			key, err := inv.arg(0, "id or uid")
+			if err != nil {
+				return nil, err
+			}
+			return dataSource(inv.client, key, *byName)
+		}
+	}},
+	{"datasources", "create", "[-file path]", "Create a data source from its JSON definition.", idColumns, func(fs *flag.FlagSet) runFunc {
+		file := fs.String("file", "-", "JSON file of the data source, - for the standard input")
+		return func(inv *invocation) (interface{}, error) {
+			ds := &gapi.DataSource{}
+			if err := inv.readJSON(*file, ds); err != nil {
+				return nil, err
+			}
+			id, err := inv.client.NewDataSource(ds)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]int64{"id": id}, nil
+		}
+	}},
+	{"datasources", "update", "[-file path] [<id-or-uid>]", "Replace a data source with a JSON definition, identified by the argument or the id of the definition.", nil, func(fs *flag.FlagSet) runFunc {
+		file := fs.String("file", "-", "JSON file of the data source, - for the standard input")
+		return func(inv *invocation) (interface{}, error) {
+			ds := &gapi.DataSource{}
+			if err := inv.readJSON(*file, ds); err != nil {
+				return nil, err
+			}
+			if len(inv.args) > 0 {
+				current, err := dataSource(inv.client, inv.args[0], false)
+				if err != nil {
+					return nil, err
+				}
+				ds.Id = current.Id
+			}
+			if ds.Id == 0 {
+				return nil, fmt.Errorf("Missing the id of the data source")
+			}
+			return nil, inv.client.UpdateDataSource(ds)
+		}
+	}},
+	{"datasources", "delete", "<id-or-uid>", "Delete a data source.", nil, noFlags(func(inv *invocation) (interface{}, error) {
+		key, err := inv.arg(0, "id or uid")
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			ds, err := inv.client.DataSourceByUID(key)
+			if err != nil {
+				return nil, err
+			}
+			id = ds.Id
+		}
+		return nil, inv.client.DeleteDataSource(id)
+	})},
+
+	{"users", "list", "", "List the users.", userColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		return inv.client.Users()
+	})},
+	{"users", "lookup", "<login-or-email>", "Show a user by login or email.", userLookupColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		loginOrEmail, err := inv.arg(0, "login or email")
+		if err != nil {
+			return nil, err
+		}
+		return inv.client.UserByEmail(loginOrEmail)
+	})},
+
+	{"alert-notifications", "get", "<id>", "Show an alert notification channel.", alertNotificationColumns, noFlags(func(inv *invocation) (interface{}, error) {
+		id, err := inv.intArg(0, "id")
+		if err != nil {
+			return nil, err
+		}
+		return inv.client.AlertNotification(id)
+	})},
+	{"alert-notifications", "create", "[-file path]", "Create an alert notification channel from its JSON definition.", idColumns, func(fs *flag.FlagSet) runFunc {
+		file := fs.String("file", "-", "JSON file of the channel, - for the standard input")
+		return func(inv *invocation) (interface{}, error) {
+			a := &gapi.AlertNotification{}
+			if err := inv.readJSON(*file, a); err != nil {
+				return nil, err
+			}
+			id, err := inv.client.NewAlertNotification(a)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]int64{"id": id}, nil
+		}
+	}},
+	{"alert-notifications", "update", "[-file path] [<id>]", "Replace an alert notification channel with a JSON definition, identified by the argument or the id of the definition.", nil, func(fs *flag.FlagSet) runFunc {
+		file := fs.String("file", "-", "JSON file of the channel, - for the standard input")
+		return func(inv *invocation) (interface{}, error) {
+			a := &gapi.AlertNotification{}
+			if err := inv.readJSON(*file, a); err != nil {
+				return nil, err
+			}
+			if len(inv.args) > 0 {
+				id, err := inv.intArg(0, "id")
+				if err != nil {
+					return nil, err
+				}
+				a.Id = id
+			}
+			if a.Id == 0 {
+				return nil, fmt.Errorf("Missing the id of the channel")
+			}
+			return nil, inv.client.UpdateAlertNotification(a)
+		}
+	}},
+	{"alert-notifications", "delete", "<id>", "Delete an alert notification channel.", nil, noFlags(func(inv *invocation) (interface{}, error) {
+		id, err := inv.intArg(0, "id")
+		if err != nil {
+			return nil, err
+		}
+		return nil, inv.client.DeleteAlertNotification(id)
+	})},
+}
+
+// dataSource looks a data source up by name, or by id when key is a number
+// and by uid otherwise.
+func dataSource(client *gapi.Client, key string, byName bool) (*gapi.DataSource, error) {
+	if byName {
+		return client.DataSourceByName(key)
+	}
+	if id, err := strconv.ParseInt(key, 10, 64); err == nil {
+		return client.DataSource(id)
+	}
+	return client.DataSourceByUID(key)
+}
+
+func findCommand(resource, action string) (*command, bool) {
+	for i := range commands {
+		if commands[i].resource == resource && commands[i].action == action {
+			return &commands[i], true
+		}
+	}
+	return nil, false
+}
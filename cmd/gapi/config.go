@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// instance is a Grafana the tool can talk to.
+type instance struct {
+	URL string `yaml:"url"`
+	// Auth is an API key or token, or user:password credentials.
+	Auth  string `yaml:"auth"`
+	OrgID int64  `yaml:"orgId,omitempty"`
+}
+
+// config is the configuration file, e.g.
+//
+//	current: prod
+//	instances:
+//	  prod:
+//	    url: https://grafana.example.com
+//	    auth: glsa_...
+//	  staging:
+//	    url: https://grafana.staging.example.com
+//	    auth: admin:admin
+//	    orgId: 2
+type config struct {
+	// Current is the instance used unless another is selected.
+	Current   string              `yaml:"current"`
+	Instances map[string]instance `yaml:"instances"`
+}
+
+// defaultConfigPath returns the path of the configuration file used unless
+// another is given.
+func defaultConfigPath(getenv func(string) string) string {
+	dir := getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home := getenv("HOME")
+		if home == "" {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gapi", "config.yaml")
+}
+
+// loadConfig reads the configuration file at path. A missing file is an empty
+// configuration unless required.
+func loadConfig(path string, required bool) (*config, error) {
+	cfg := &config{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("Invalid config file %s: %s", path, err)
+	}
+	return cfg, nil
+}
+
+// resolve returns the instance to use: the one named, else the one given by
+// GRAFANA_URL and GRAFANA_AUTH, else the current one of the configuration,
+// else its only one. GRAFANA_ORG_ID overrides the org of instances that are
+// not named. The environment is ignored for named instances, so that their
+// credentials are never sent to another URL.
+func (cfg *config) resolve(name string, getenv func(string) string) (instance, error) {
+	if name != "" {
+		inst, ok := cfg.Instances[name]
+		if !ok {
+			names := make([]string, 0, len(cfg.Instances))
+			for n := range cfg.Instances {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return inst, fmt.Errorf("Unknown instance %q, configured instances: %v", name, names)
+		}
+		return inst, nil
+	}
+
+	var inst instance
+	url, auth := getenv("GRAFANA_URL"), getenv("GRAFANA_AUTH")
+	switch {
+	case url != "" && auth != "":
+		inst = instance{URL: url, Auth: auth}
+	case url != "" || auth != "":
+		return inst, fmt.Errorf("GRAFANA_URL and GRAFANA_AUTH must be set together")
+	case cfg.Current != "":
+		var ok bool
+		if inst, ok = cfg.Instances[cfg.Current]; !ok {
+			return inst, fmt.Errorf("Unknown current instance %q", cfg.Current)
+		}
+	case len(cfg.Instances) == 1:
+		for _, only := range cfg.Instances {
+			inst = only
+		}
+	}
+	if orgID := getenv("GRAFANA_ORG_ID"); orgID != "" {
+		id, err := strconv.ParseInt(orgID, 10, 64)
+		if err != nil {
+			return inst, fmt.Errorf("Invalid GRAFANA_ORG_ID %q", orgID)
+		}
+		inst.OrgID = id
+	}
+
+	if inst.URL == "" {
+		return inst, fmt.Errorf("No Grafana URL, set GRAFANA_URL and GRAFANA_AUTH or configure an instance")
+	}
+	if inst.Auth == "" {
+		return inst, fmt.Errorf("No credentials for %s, set the auth of the instance", inst.URL)
+	}
+	return inst, nil
+}
@@ -0,0 +1,156 @@
+// Command gapi runs the methods of the gapi client from the command line.
+//
+//	gapi [flags] <resource> <action> [action flags] [arguments]
+//
+// The Grafana to talk to is the instance of the configuration file
+// (~/.config/gapi/config.yaml, or the file given with -config or GAPI_CONFIG)
+// named with -instance or GAPI_INSTANCE. Without one, it is read from the
+// GRAFANA_URL and GRAFANA_AUTH (an API key or token, or user:password)
+// environment variables, which must be set together, else it is the current
+// instance of the file. GRAFANA_ORG_ID selects the organization of instances
+// that are not named. Results are printed as a table, JSON or YAML.
+//
+//	gapi -instance staging -o json dashboards get my-dashboard
+//	gapi dashboards save -file dashboard.json -overwrite
+//	gapi -dry-run datasources delete prometheus
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+// app holds the environment of a run, overridden by tests.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func main() {
+	a := &app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "Usage: gapi [flags] <resource> <action> [action flags] [arguments]")
+	fmt.Fprintln(a.stderr, "\nFlags:")
+	fs.SetOutput(a.stderr)
+	fs.PrintDefaults()
+	fmt.Fprintln(a.stderr, "\nCommands:")
+	sorted := append([]command(nil), commands...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].resource < sorted[j].resource })
+	for _, cmd := range sorted {
+		fmt.Fprintf(a.stderr, "  %s %s %s\n    \t%s\n", cmd.resource, cmd.action, cmd.args, cmd.help)
+	}
+}
+
+// run runs the command line and returns the exit code: 1 for failures of the
+// command, 2 for invalid command lines.
+func (a *app) run(args []string) int {
+	fs := flag.NewFlagSet("gapi", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	configPath := fs.String("config", "", "configuration file (default $GAPI_CONFIG or ~/.config/gapi/config.yaml)")
+	instanceName := fs.String("instance", "", "instance of the configuration file to use (default $GAPI_INSTANCE or its current one)")
+	output := fs.String("o", "table", "output format: table, json or yaml")
+	orgID := fs.Int64("org", 0, "id of the organization to act on (default $GRAFANA_ORG_ID or the current one of the user)")
+	dryRun := fs.Bool("dry-run", false, "print the changes the command would make instead of making them")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			a.usage(fs)
+			return 0
+		}
+		fmt.Fprintln(a.stderr, err)
+		a.usage(fs)
+		return 2
+	}
+	if fs.NArg() < 2 {
+		a.usage(fs)
+		return 2
+	}
+	cmd, ok := findCommand(fs.Arg(0), fs.Arg(1))
+	if !ok {
+		fmt.Fprintf(a.stderr, "Unknown command %q\n\n", strings.Join(fs.Args()[:2], " "))
+		a.usage(fs)
+		return 2
+	}
+
+	cmdFlags := flag.NewFlagSet(cmd.resource+" "+cmd.action, flag.ContinueOnError)
+	cmdFlags.SetOutput(a.stderr)
+	cmdFlags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: gapi %s %s %s\n\n%s\n", cmd.resource, cmd.action, cmd.args, cmd.help)
+		cmdFlags.PrintDefaults()
+	}
+	run := cmd.setup(cmdFlags)
+	if err := cmdFlags.Parse(fs.Args()[2:]); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+
+	if *configPath == "" {
+		*configPath = a.getenv("GAPI_CONFIG")
+	}
+	required := *configPath != ""
+	if !required {
+		*configPath = defaultConfigPath(a.getenv)
+	}
+	cfg, err := loadConfig(*configPath, required)
+	if err != nil {
+		fmt.Fprintln(a.stderr, err)
+		return 1
+	}
+	if *instanceName == "" {
+		*instanceName = a.getenv("GAPI_INSTANCE")
+	}
+	inst, err := cfg.resolve(*instanceName, a.getenv)
+	if err != nil {
+		fmt.Fprintln(a.stderr, err)
+		return 1
+	}
+
+	client, err := gapi.New(inst.Auth, inst.URL)
+	if err != nil {
+		fmt.Fprintln(a.stderr, err)
+		return 1
+	}
+	if *orgID == 0 {
+		*orgID = inst.OrgID
+	}
+	if *orgID != 0 {
+		client = client.WithOrgID(*orgID)
+	}
+	var plan *gapi.Plan
+	if *dryRun {
+		client, plan = client.DryRun()
+	}
+
+	result, err := run(&invocation{app: a, client: client, args: cmdFlags.Args()})
+	if err != nil {
+		fmt.Fprintln(a.stderr, err)
+		return 1
+	}
+	if plan != nil && len(plan.Requests()) > 0 {
+		if *output == "table" {
+			fmt.Fprint(a.stdout, plan)
+			return 0
+		}
+		result = plan
+	}
+	if result == nil {
+		return 0
+	}
+	if err := write(a.stdout, *output, result, cmd.columns); err != nil {
+		fmt.Fprintln(a.stderr, err)
+		return 1
+	}
+	return 0
+}
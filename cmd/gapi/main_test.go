@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vanugrah/go-grafana-api/gapitest"
+	"gopkg.in/yaml.v3"
+)
+
+// runGapi runs a command line against a Grafana configured by env.
+func runGapi(t *testing.T, env map[string]string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	a := &app{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(name string) string { return env[name] },
+	}
+	code := a.run(args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCommands(t *testing.T) {
+	server := gapitest.NewServer()
+	defer server.Close()
+	env := map[string]string{"GRAFANA_URL": server.URL, "GRAFANA_AUTH": "admin:admin"}
+
+	code, out, stderr := runGapi(t, env, "", "folders", "create", "-uid", "ops", "Ops")
+	if code != 0 || !strings.Contains(out, "ops") {
+		t.Fatalf("Unexpected exit code %d, output %q, errors %q", code, out, stderr)
+	}
+	code, out, _ = runGapi(t, env, `{"title":"Latency","uid":"lat"}`, "dashboards", "save")
+	if code != 0 {
+		t.Fatalf("Unexpected exit code %d", code)
+	}
+
+	code, out, _ = runGapi(t, env, "", "dashboards", "get", "lat")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if code != 0 || len(lines) != 2 || !strings.HasPrefix(lines[0], "UID") || !strings.Contains(lines[1], "Latency") {
+		t.Errorf("Expected a table with a header and a row, got %q", out)
+	}
+
+	code, out, _ = runGapi(t, env, "", "-o", "json", "folders", "list")
+	var folders []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &folders); code != 0 || err != nil || len(folders) != 1 || folders[0]["uid"] != "ops" {
+		t.Errorf("Expected the folders as JSON, got %q", out)
+	}
+
+	code, out, _ = runGapi(t, env, "", "-o", "yaml", "users", "lookup", "admin")
+	user := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(out), &user); code != 0 || err != nil || user["login"] != "admin" || user["id"] != 1 {
+		t.Errorf("Expected the user as YAML, got %q", out)
+	}
+
+	code, out, _ = runGapi(t, env, "", "-dry-run", "dashboards", "delete", "lat")
+	if code != 0 || !strings.Contains(out, "DELETE /api/dashboards/uid/lat") {
+		t.Errorf("Expected the planned deletion, got %q", out)
+	}
+	if code, _, _ = runGapi(t, env, "", "dashboards", "get", "lat"); code != 0 {
+		t.Errorf("A dry run should not delete the dashboard")
+	}
+
+	if code, _, _ = runGapi(t, env, "", "dashboards", "delete", "lat"); code != 0 {
+		t.Errorf("Unexpected exit code %d", code)
+	}
+	code, _, stderr = runGapi(t, env, "", "dashboards", "get", "lat")
+	if code != 1 || !strings.Contains(stderr, "404") {
+		t.Errorf("Expected a 404 for a deleted dashboard, got %d %q", code, stderr)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if code, _, stderr := runGapi(t, nil, "", "dashboards", "frobnicate"); code != 2 || !strings.Contains(stderr, "Unknown command") {
+		t.Errorf("Expected an unknown command, got %d %q", code, stderr)
+	}
+	if code, _, stderr := runGapi(t, nil, "", "orgs", "list"); code != 1 || !strings.Contains(stderr, "GRAFANA_URL") {
+		t.Errorf("Expected a missing URL, got %d %q", code, stderr)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	server := gapitest.NewServer()
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "gapi-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	config := "current: prod\ninstances:\n  prod:\n    url: http://127.0.0.1:1\n    auth: key\n  test:\n    url: " + server.URL + "\n    auth: admin:admin\n"
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, stderr := runGapi(t, map[string]string{"GAPI_CONFIG": path}, "", "-instance", "test", "orgs", "list")
+	if code != 0 || !strings.Contains(out, "Main Org.") {
+		t.Errorf("Expected the orgs of the test instance, got %d %q %q", code, out, stderr)
+	}
+	code, out, _ = runGapi(t, map[string]string{"GRAFANA_URL": server.URL, "GRAFANA_AUTH": "admin:admin"}, "", "-config", path, "orgs", "list")
+	if code != 0 || !strings.Contains(out, "Main Org.") {
+		t.Errorf("The environment should override the current instance, got %d %q", code, out)
+	}
+	code, out, stderr = runGapi(t, map[string]string{"GRAFANA_URL": "http://127.0.0.1:1", "GRAFANA_AUTH": "key"}, "", "-config", path, "-instance", "test", "orgs", "list")
+	if code != 0 || !strings.Contains(out, "Main Org.") {
+		t.Errorf("The environment should not override a named instance, got %d %q %q", code, out, stderr)
+	}
+	if code, _, stderr = runGapi(t, map[string]string{"GRAFANA_URL": server.URL}, "", "-config", path, "orgs", "list"); code != 1 || !strings.Contains(stderr, "must be set together") {
+		t.Errorf("GRAFANA_URL should not be used with the auth of an instance, got %d %q", code, stderr)
+	}
+	if code, _, stderr = runGapi(t, nil, "", "-config", path, "-instance", "dev", "orgs", "list"); code != 1 || !strings.Contains(stderr, `Unknown instance "dev"`) {
+		t.Errorf("Expected an unknown instance, got %d %q", code, stderr)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generic converts a result to the maps, slices and values of its JSON
+// encoding, so that every format uses the JSON field names.
+func generic(result interface{}) (interface{}, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	err = dec.Decode(&v)
+	return v, err
+}
+
+// write prints a result as a table of columns, JSON or YAML. Columns are JSON
+// fields, with dots to reach nested ones, e.g. "meta.version".
+func write(w io.Writer, format string, result interface{}, columns []string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case "yaml":
+		v, err := generic(result)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(yamlValue(v))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case "table":
+		return writeTable(w, result, columns)
+	}
+	return fmt.Errorf("Unknown output format %q, expected table, json or yaml", format)
+}
+
+// yamlValue turns the JSON numbers of v into YAML numbers.
+func yamlValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			v[k] = yamlValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlValue(item)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+func writeTable(w io.Writer, result interface{}, columns []string) error {
+	v, err := generic(result)
+	if err != nil {
+		return err
+	}
+	rows, ok := v.([]interface{})
+	if !ok {
+		rows = []interface{}{v}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(column[strings.LastIndex(column, ".")+1:])
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = cell(lookup(row, column))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func lookup(v interface{}, column string) interface{} {
+	for _, field := range strings.Split(column, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[field]
+	}
+	return v
+}
+
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,83 @@
+package gapi
+
+import (
+	"fmt"
+)
+
+// The methods below act on behalf of the user the client authenticates as.
+// They are not available to API keys, which do not belong to a user.
+
+func (c *Client) CurrentUser() (User, error) {
+	return c.userBy("CurrentUser", "/api/user", nil)
+}
+
+// UpdateCurrentUser updates the email, name, login and theme of the user.
+// Empty email, name, login and theme are left unchanged.
+func (c *Client) UpdateCurrentUser(user User) error {
+	body := userUpdate{
+		Email: user.Email,
+		Name:  user.Name,
+		Login: user.Login,
+		Theme: user.Theme,
+	}
+	return c.sendJSON("UpdateCurrentUser", "PUT", "/api/user", nil, body, nil)
+}
+
+func (c *Client) ChangeCurrentUserPassword(oldPassword, newPassword string) error {
+	dataMap := map[string]string{
+		"oldPassword": oldPassword,
+		"newPassword": newPassword,
+		"confirmNew":  newPassword,
+	}
+	return c.sendJSON("ChangeCurrentUserPassword", "PUT", "/api/user/password", nil, dataMap, nil)
+}
+
+func (c *Client) CurrentUserOrgs() ([]UserOrg, error) {
+	orgs := make([]UserOrg, 0)
+	err := c.getJSON("CurrentUserOrgs", "/api/user/orgs", &orgs)
+	return orgs, err
+}
+
+// SwitchCurrentUserOrg changes the current organization of the user, which
+// requests not scoped with WithOrgID act on.
+func (c *Client) SwitchCurrentUserOrg(orgId int64) error {
+	return c.sendJSON("SwitchCurrentUserOrg", "POST", fmt.Sprintf("/api/user/using/%d", orgId), nil, nil, nil)
+}
+
+func (c *Client) CurrentUserTeams() ([]Team, error) {
+	teams := make([]Team, 0)
+	err := c.getJSON("CurrentUserTeams", "/api/user/teams", &teams)
+	return teams, err
+}
+
+func (c *Client) StarDashboard(uid string) error {
+	return c.sendJSON("StarDashboard", "POST", fmt.Sprintf("/api/user/stars/dashboard/uid/%s", uid), nil, nil, nil)
+}
+
+func (c *Client) UnstarDashboard(uid string) error {
+	return c.sendJSON("UnstarDashboard", "DELETE", fmt.Sprintf("/api/user/stars/dashboard/uid/%s", uid), nil, nil, nil)
+}
+
+func (c *Client) CurrentUserPreferences() (Preferences, error) {
+	prefs := Preferences{}
+	err := c.getJSON("CurrentUserPreferences", "/api/user/preferences", &prefs)
+	return prefs, err
+}
+
+func (c *Client) UpdateCurrentUserPreferences(prefs Preferences) error {
+	return c.sendJSON("UpdateCurrentUserPreferences", "PUT", "/api/user/preferences", nil, prefs, nil)
+}
+
+// CurrentUserAuthTokens lists the sessions of the user.
+func (c *Client) CurrentUserAuthTokens() ([]UserAuthToken, error) {
+	tokens := make([]UserAuthToken, 0)
+	err := c.getJSON("CurrentUserAuthTokens", "/api/user/auth-tokens", &tokens)
+	return tokens, err
+}
+
+func (c *Client) RevokeCurrentUserAuthToken(tokenId int64) error {
+	dataMap := map[string]int64{
+		"authTokenId": tokenId,
+	}
+	return c.sendJSON("RevokeCurrentUserAuthToken", "POST", "/api/user/revoke-auth-token", nil, dataMap, nil)
+}
@@ -0,0 +1,132 @@
+package gapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getCurrentUserJSON            = `{"id":1,"email":"admin@localhost","name":"Admin","login":"admin","theme":"light","orgId":1,"isGrafanaAdmin":true,"isDisabled":false}`
+	getCurrentUserOrgsJSON        = `[{"orgId":1,"name":"Main Org.","role":"Admin"},{"orgId":2,"name":"Test Org.","role":"Viewer"}]`
+	getCurrentUserPreferencesJSON = `{"theme":"light","homeDashboardUID":"home","timezone":"browser","weekStart":""}`
+	currentUserActionJSON         = `{"message":"ok"}`
+)
+
+func TestCurrentUser(t *testing.T) {
+	server, client := gapiTestTools(200, getCurrentUserJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentUser()
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	user := User{
+		Id:             1,
+		Email:          "admin@localhost",
+		Name:           "Admin",
+		Login:          "admin",
+		Theme:          "light",
+		IsAdmin:        true,
+		IsGrafanaAdmin: true,
+	}
+	if resp != user {
+		t.Error("Not correctly parsing returned user.")
+	}
+}
+
+func TestChangeCurrentUserPassword(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != "PUT" || body["oldPassword"] != "old" || body["confirmNew"] != "new" {
+			t.Errorf("Unexpected request %s %v", r.Method, body)
+		}
+		w.Write([]byte(currentUserActionJSON))
+	}))
+	defer server.Close()
+
+	err := client.ChangeCurrentUserPassword("old", "new")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCurrentUserOrgs(t *testing.T) {
+	server, client := gapiTestTools(200, getCurrentUserOrgsJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentUserOrgs()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 2 || resp[1] != (UserOrg{OrgId: 2, Name: "Test Org.", Role: RoleViewer}) {
+		t.Error("Not correctly parsing returned orgs.")
+	}
+}
+
+func TestSwitchCurrentUserOrg(t *testing.T) {
+	server, client := gapiTestTools(200, currentUserActionJSON)
+	defer server.Close()
+
+	err := client.SwitchCurrentUserOrg(2)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStarDashboard(t *testing.T) {
+	server, client := gapiTestTools(200, currentUserActionJSON)
+	defer server.Close()
+
+	if err := client.StarDashboard("home"); err != nil {
+		t.Error(err)
+	}
+	if err := client.UnstarDashboard("home"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCurrentUserPreferences(t *testing.T) {
+	server, client := gapiTestTools(200, getCurrentUserPreferencesJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentUserPreferences()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if resp != (Preferences{Theme: "light", HomeDashboardUID: "home", Timezone: "browser"}) {
+		t.Error("Not correctly parsing returned preferences.")
+	}
+}
+
+func TestCurrentUserAuthTokens(t *testing.T) {
+	server, client := gapiTestTools(200, getUserAuthTokensJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentUserAuthTokens()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 1 || resp[0].Id != 361 {
+		t.Error("Not correctly parsing returned auth tokens.")
+	}
+}
+
+func TestRevokeCurrentUserAuthTokenError(t *testing.T) {
+	server, client := gapiTestTools(400, `{"message":"Cannot revoke active user auth token"}`)
+	defer server.Close()
+
+	err := client.RevokeCurrentUserAuthToken(361)
+	if gerr, ok := err.(*GrafanaError); !ok || gerr.StatusCode != 400 {
+		t.Errorf("Expected a 400 GrafanaError, got %v", err)
+	}
+}
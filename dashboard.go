@@ -58,7 +58,7 @@ func (c *Client) SaveDashboard(d *DashboardSaveOpts) (*DashboardSaveResponse, er
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to marshall dashboard JSON")
 	}
-	req, err := c.newRequest("POST", "/api/dashboards/db", nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("SaveDashboard", "POST", "/api/dashboards/db", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +87,7 @@ func (c *Client) SaveDashboard(d *DashboardSaveOpts) (*DashboardSaveResponse, er
 
 func (c *Client) GetDashboardByUID(uid string) (*Dashboard, error) {
 	path := fmt.Sprintf("/api/dashboards/uid/%s", uid)
-	req, err := c.newRequest("GET", path, nil, nil)
+	req, err := c.newRequest("GetDashboardByUID", "GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +116,7 @@ func (c *Client) GetDashboardByUID(uid string) (*Dashboard, error) {
 
 func (c *Client) DeleteDashboardByUID(uid string) error {
 	path := fmt.Sprintf("/api/dashboards/uid/%s", uid)
-	req, err := c.newRequest("DELETE", path, nil, nil)
+	req, err := c.newRequest("DeleteDashboardByUID", "DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
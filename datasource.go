@@ -3,13 +3,13 @@ package gapi
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 )
 
 type DataSource struct {
 	Id     int64  `json:"id,omitempty"`
+	Uid    string `json:"uid,omitempty"`
 	Name   string `json:"name"`
 	Type   string `json:"type"`
 	URL    string `json:"url"`
@@ -26,8 +26,18 @@ type DataSource struct {
 	BasicAuthUser     string `json:"basicAuthUser,omitempty"`
 	BasicAuthPassword string `json:"basicAuthPassword,omitempty"`
 
+	WithCredentials bool `json:"withCredentials,omitempty"`
+	Editable        bool `json:"editable,omitempty"`
+	// Version is incremented by Grafana on every update. Updates sending an
+	// older version are rejected with a 409.
+	Version int64 `json:"version,omitempty"`
+
 	JSONData       JSONData       `json:"jsonData,omitempty"`
 	SecureJSONData SecureJSONData `json:"secureJsonData,omitempty"`
+
+	// SecureJSONFields is set by Grafana and reports which secureJsonData
+	// keys have a value, since the values themselves are never returned.
+	SecureJSONFields map[string]bool `json:"secureJsonFields,omitempty"`
 }
 
 // JSONData is a representation of the datasource `jsonData` property
@@ -36,12 +46,114 @@ type JSONData struct {
 	AuthType                string `json:"authType,omitempty"`
 	CustomMetricsNamespaces string `json:"customMetricsNamespaces,omitempty"`
 	DefaultRegion           string `json:"defaultRegion,omitempty"`
+
+	// Extra holds the keys not modelled above, e.g. those of other
+	// datasource types, so that they survive a read followed by an update.
+	Extra map[string]interface{} `json:"-"`
+}
+
+func (d JSONData) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(d.Extra, map[string]string{
+		"assumeRoleArn":           d.AssumeRoleArn,
+		"authType":                d.AuthType,
+		"customMetricsNamespaces": d.CustomMetricsNamespaces,
+		"defaultRegion":           d.DefaultRegion,
+	})
+}
+
+func (d *JSONData) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalExtra(b)
+	if err != nil {
+		return err
+	}
+	d.AssumeRoleArn = takeString(m, "assumeRoleArn")
+	d.AuthType = takeString(m, "authType")
+	d.CustomMetricsNamespaces = takeString(m, "customMetricsNamespaces")
+	d.DefaultRegion = takeString(m, "defaultRegion")
+	d.Extra = extra(m)
+	return nil
 }
 
 // SecureJSONData is a representation of the datasource `secureJsonData` property
 type SecureJSONData struct {
-	AccessKey string `json:"accessKey,omitempty"`
-	SecretKey string `json:"secretKey,omitempty"`
+	AccessKey         string `json:"accessKey,omitempty"`
+	SecretKey         string `json:"secretKey,omitempty"`
+	Password          string `json:"password,omitempty"`
+	BasicAuthPassword string `json:"basicAuthPassword,omitempty"`
+
+	// Extra holds the keys not modelled above, e.g. httpHeaderValue1 or
+	// tlsClientKey.
+	Extra map[string]interface{} `json:"-"`
+}
+
+func (d SecureJSONData) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(d.Extra, map[string]string{
+		"accessKey":         d.AccessKey,
+		"secretKey":         d.SecretKey,
+		"password":          d.Password,
+		"basicAuthPassword": d.BasicAuthPassword,
+	})
+}
+
+func (d *SecureJSONData) UnmarshalJSON(b []byte) error {
+	m, err := unmarshalExtra(b)
+	if err != nil {
+		return err
+	}
+	d.AccessKey = takeString(m, "accessKey")
+	d.SecretKey = takeString(m, "secretKey")
+	d.Password = takeString(m, "password")
+	d.BasicAuthPassword = takeString(m, "basicAuthPassword")
+	d.Extra = extra(m)
+	return nil
+}
+
+// marshalWithExtra marshals the non-empty modelled fields of an object over
+// its extra keys.
+func marshalWithExtra(extra map[string]interface{}, fields map[string]string) ([]byte, error) {
+	m := make(map[string]interface{}, len(extra)+len(fields))
+	for k, v := range extra {
+		m[k] = v
+	}
+	for k, v := range fields {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	return json.Marshal(m)
+}
+
+func unmarshalExtra(b []byte) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// takeString removes a modelled key from m. Values of another type than
+// string are left in m, so they are kept as extra keys.
+func takeString(m map[string]interface{}, key string) string {
+	v, ok := m[key].(string)
+	if ok {
+		delete(m, key)
+	}
+	return v
+}
+
+func extra(m map[string]interface{}) map[string]interface{} {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// DataSourceSecrets holds the secrets set by RotateDataSourceSecrets. Empty
+// fields are left untouched.
+type DataSourceSecrets struct {
+	Password          string
+	BasicAuthPassword string
+	SecureJSONData    SecureJSONData
 }
 
 func (c *Client) NewDataSource(s *DataSource) (int64, error) {
@@ -49,7 +161,7 @@ func (c *Client) NewDataSource(s *DataSource) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	req, err := c.newRequest("POST", "/api/datasources", nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("NewDataSource", "POST", "/api/datasources", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return 0, err
 	}
@@ -59,7 +171,10 @@ func (c *Client) NewDataSource(s *DataSource) (int64, error) {
 		return 0, err
 	}
 	if resp.StatusCode != 200 {
-		return 0, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return 0, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 
 	data, err = ioutil.ReadAll(resp.Body)
@@ -80,7 +195,7 @@ func (c *Client) UpdateDataSource(s *DataSource) error {
 	if err != nil {
 		return err
 	}
-	req, err := c.newRequest("PUT", path, nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("UpdateDataSource", "PUT", path, nil, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -90,7 +205,10 @@ func (c *Client) UpdateDataSource(s *DataSource) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 
 	return nil
@@ -98,7 +216,7 @@ func (c *Client) UpdateDataSource(s *DataSource) error {
 
 func (c *Client) DataSource(id int64) (*DataSource, error) {
 	path := fmt.Sprintf("/api/datasources/%d", id)
-	req, err := c.newRequest("GET", path, nil, nil)
+	req, err := c.newRequest("DataSource", "GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +226,72 @@ func (c *Client) DataSource(id int64) (*DataSource, error) {
 		return nil, err
 	}
 	if resp.StatusCode != 200 {
-		return nil, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DataSource{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func (c *Client) DataSources() ([]*DataSource, error) {
+	req, err := c.newRequest("DataSources", "GET", "/api/datasources", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*DataSource, 0)
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func (c *Client) DataSourceByUID(uid string) (*DataSource, error) {
+	return c.dataSourceBy("DataSourceByUID", fmt.Sprintf("/api/datasources/uid/%s", uid))
+}
+
+func (c *Client) DataSourceByName(name string) (*DataSource, error) {
+	return c.dataSourceBy("DataSourceByName", fmt.Sprintf("/api/datasources/name/%s", name))
+}
+
+func (c *Client) dataSourceBy(name, path string) (*DataSource, error) {
+	req, err := c.newRequest(name, "GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 
 	data, err := ioutil.ReadAll(resp.Body)
@@ -123,7 +306,7 @@ func (c *Client) DataSource(id int64) (*DataSource, error) {
 
 func (c *Client) DeleteDataSource(id int64) error {
 	path := fmt.Sprintf("/api/datasources/%d", id)
-	req, err := c.newRequest("DELETE", path, nil, nil)
+	req, err := c.newRequest("DeleteDataSource", "DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -133,8 +316,41 @@ func (c *Client) DeleteDataSource(id int64) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 
 	return nil
 }
+
+// RotateDataSourceSecrets replaces the secrets of the datasource with the
+// non-empty fields of secrets and leaves the rest of its configuration as is.
+// Grafana keeps any secureJsonData key not sent, so only the given secrets
+// change. It returns the secureJsonFields reported after the update.
+func (c *Client) RotateDataSourceSecrets(id int64, secrets DataSourceSecrets) (map[string]bool, error) {
+	ds, err := c.DataSource(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if secrets.Password != "" {
+		ds.Password = secrets.Password
+	}
+	if secrets.BasicAuthPassword != "" {
+		ds.BasicAuthPassword = secrets.BasicAuthPassword
+	}
+	ds.SecureJSONData = secrets.SecureJSONData
+	ds.SecureJSONFields = nil
+
+	if err = c.UpdateDataSource(ds); err != nil {
+		return nil, err
+	}
+
+	ds, err = c.DataSource(id)
+	if err != nil {
+		return nil, err
+	}
+	return ds.SecureJSONFields, nil
+}
@@ -0,0 +1,168 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DataSourcePermissionType is the level of access granted by a datasource
+// permission. Datasource permissions are a Grafana Enterprise feature.
+type DataSourcePermissionType int
+
+const (
+	DataSourcePermissionQuery DataSourcePermissionType = 1
+	DataSourcePermissionEdit  DataSourcePermissionType = 2
+)
+
+type DataSourcePermissions struct {
+	DataSourceId int64                  `json:"datasourceId"`
+	Enabled      bool                   `json:"enabled"`
+	Permissions  []DataSourcePermission `json:"permissions"`
+}
+
+// DataSourcePermission grants a user, a team or a built-in role access to a
+// datasource. Exactly one of UserId, TeamId and BuiltinRole is set.
+type DataSourcePermission struct {
+	Id             int64                    `json:"id"`
+	DataSourceId   int64                    `json:"datasourceId"`
+	UserId         int64                    `json:"userId,omitempty"`
+	UserLogin      string                   `json:"userLogin,omitempty"`
+	UserEmail      string                   `json:"userEmail,omitempty"`
+	TeamId         int64                    `json:"teamId,omitempty"`
+	Team           string                   `json:"team,omitempty"`
+	BuiltinRole    Role                     `json:"builtinRole,omitempty"`
+	Permission     DataSourcePermissionType `json:"permission"`
+	PermissionName string                   `json:"permissionName"`
+	Created        time.Time                `json:"created"`
+	Updated        time.Time                `json:"updated"`
+}
+
+// DataSourcePermissionAddOpts is the body of a request adding a datasource
+// permission. Set exactly one of UserId, TeamId and BuiltinRole.
+type DataSourcePermissionAddOpts struct {
+	UserId      int64                    `json:"userId,omitempty"`
+	TeamId      int64                    `json:"teamId,omitempty"`
+	BuiltinRole Role                     `json:"builtinRole,omitempty"`
+	Permission  DataSourcePermissionType `json:"permission"`
+}
+
+func (c *Client) DataSourcePermissions(id int64) (*DataSourcePermissions, error) {
+	path := fmt.Sprintf("/api/datasources/%d/permissions", id)
+	req, err := c.newRequest("DataSourcePermissions", "GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DataSourcePermissions{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// EnableDataSourcePermissions restricts querying the datasource to the
+// users, teams and roles it has permissions for.
+func (c *Client) EnableDataSourcePermissions(id int64) error {
+	return c.setDataSourcePermissions("EnableDataSourcePermissions", id, "enable-permissions")
+}
+
+// DisableDataSourcePermissions lets every member of the org query the
+// datasource again.
+func (c *Client) DisableDataSourcePermissions(id int64) error {
+	return c.setDataSourcePermissions("DisableDataSourcePermissions", id, "disable-permissions")
+}
+
+func (c *Client) setDataSourcePermissions(name string, id int64, action string) error {
+	path := fmt.Sprintf("/api/datasources/%d/%s", id, action)
+	req, err := c.newRequest(name, "POST", path, nil, bytes.NewBufferString("{}"))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	return nil
+}
+
+func (c *Client) AddDataSourcePermission(id int64, perm *DataSourcePermissionAddOpts) error {
+	if perm.BuiltinRole != "" {
+		if err := perm.BuiltinRole.Validate(); err != nil {
+			return err
+		}
+	}
+	path := fmt.Sprintf("/api/datasources/%d/permissions", id)
+	data, err := json.Marshal(perm)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshall permission JSON")
+	}
+	req, err := c.newRequest("AddDataSourcePermission", "POST", path, nil, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	return nil
+}
+
+func (c *Client) RemoveDataSourcePermission(id, permissionId int64) error {
+	path := fmt.Sprintf("/api/datasources/%d/permissions/%d", id, permissionId)
+	req, err := c.newRequest("RemoveDataSourcePermission", "DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+
+	return nil
+}
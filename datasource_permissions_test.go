@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	getDataSourcePermissionsJSON   = `{"datasourceId":1,"enabled":true,"permissions":[{"id":1,"datasourceId":1,"userId":1,"userLogin":"user","userEmail":"user@test.com","permission":1,"permissionName":"Query","created":"2017-06-20T02:00:00+02:00","updated":"2017-06-20T02:00:00+02:00"},{"id":2,"datasourceId":1,"teamId":1,"team":"A Team","permission":1,"permissionName":"Query","created":"2017-06-20T02:00:00+02:00","updated":"2017-06-20T02:00:00+02:00"}]}`
+	addDataSourcePermissionJSON    = `{"message":"Datasource permission added"}`
+	removeDataSourcePermissionJSON = `{"message":"Datasource permission removed"}`
+)
+
+func TestDataSourcePermissions(t *testing.T) {
+	server, client := gapiTestTools(200, getDataSourcePermissionsJSON)
+	defer server.Close()
+
+	resp, err := client.DataSourcePermissions(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	if !resp.Enabled || len(resp.Permissions) != 2 {
+		t.Fatal("Not correctly parsing returned datasource permissions.")
+	}
+	if resp.Permissions[0].UserId != 1 || resp.Permissions[0].Permission != DataSourcePermissionQuery {
+		t.Error("Not correctly parsing returned user permission.")
+	}
+	if resp.Permissions[1].TeamId != 1 || resp.Permissions[1].Team != "A Team" {
+		t.Error("Not correctly parsing returned team permission.")
+	}
+}
+
+func TestAddDataSourcePermission(t *testing.T) {
+	server, client := gapiTestTools(200, addDataSourcePermissionJSON)
+	defer server.Close()
+
+	err := client.AddDataSourcePermission(1, &DataSourcePermissionAddOpts{
+		BuiltinRole: "Viewer",
+		Permission:  DataSourcePermissionQuery,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRemoveDataSourcePermission(t *testing.T) {
+	server, client := gapiTestTools(200, removeDataSourcePermissionJSON)
+	defer server.Close()
+
+	err := client.RemoveDataSourcePermission(1, 2)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEnableDataSourcePermissionsError(t *testing.T) {
+	server, client := gapiTestTools(404, `{"message":"Data source not found"}`)
+	defer server.Close()
+
+	err := client.EnableDataSourcePermissions(1)
+	if gerr, ok := err.(*GrafanaError); !ok || gerr.StatusCode != 404 {
+		t.Errorf("Expected a 404 GrafanaError, got %v", err)
+	}
+}
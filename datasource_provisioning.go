@@ -0,0 +1,195 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DataSourceProvisioning is the content of a Grafana datasource provisioning
+// file, e.g. `provisioning/datasources/default.yaml`.
+type DataSourceProvisioning struct {
+	APIVersion        int64
+	DataSources       []DataSource
+	DeleteDataSources []DataSourceDeleteRef
+}
+
+// DataSourceDeleteRef identifies a datasource listed under `deleteDatasources`.
+type DataSourceDeleteRef struct {
+	Name  string `json:"name" yaml:"name"`
+	OrgId int64  `json:"orgId,omitempty" yaml:"orgId,omitempty"`
+}
+
+// provisionedDataSource is a single entry of the `datasources` list. jsonData
+// and secureJsonData are kept untyped here and converted to JSONData and
+// SecureJSONData through their JSON representation, which keeps the keys
+// they do not model in Extra.
+type provisionedDataSource struct {
+	OrgId             int64                  `json:"orgId,omitempty" yaml:"orgId,omitempty"`
+	Name              string                 `json:"name" yaml:"name"`
+	Type              string                 `json:"type" yaml:"type"`
+	Access            string                 `json:"access,omitempty" yaml:"access,omitempty"`
+	Uid               string                 `json:"uid,omitempty" yaml:"uid,omitempty"`
+	URL               string                 `json:"url,omitempty" yaml:"url,omitempty"`
+	User              string                 `json:"user,omitempty" yaml:"user,omitempty"`
+	Password          string                 `json:"password,omitempty" yaml:"password,omitempty"`
+	Database          string                 `json:"database,omitempty" yaml:"database,omitempty"`
+	BasicAuth         bool                   `json:"basicAuth,omitempty" yaml:"basicAuth,omitempty"`
+	BasicAuthUser     string                 `json:"basicAuthUser,omitempty" yaml:"basicAuthUser,omitempty"`
+	BasicAuthPassword string                 `json:"basicAuthPassword,omitempty" yaml:"basicAuthPassword,omitempty"`
+	IsDefault         bool                   `json:"isDefault,omitempty" yaml:"isDefault,omitempty"`
+	WithCredentials   bool                   `json:"withCredentials,omitempty" yaml:"withCredentials,omitempty"`
+	Editable          bool                   `json:"editable,omitempty" yaml:"editable,omitempty"`
+	Version           int64                  `json:"version,omitempty" yaml:"version,omitempty"`
+	JSONData          map[string]interface{} `json:"jsonData,omitempty" yaml:"jsonData,omitempty"`
+	SecureJSONData    map[string]interface{} `json:"secureJsonData,omitempty" yaml:"secureJsonData,omitempty"`
+}
+
+type provisioningFile struct {
+	APIVersion        int64                   `json:"apiVersion" yaml:"apiVersion"`
+	DeleteDataSources []DataSourceDeleteRef   `json:"deleteDatasources,omitempty" yaml:"deleteDatasources,omitempty"`
+	DataSources       []provisionedDataSource `json:"datasources" yaml:"datasources"`
+}
+
+// ParseDataSourceProvisioning parses a datasource provisioning file. As in
+// Grafana, `$VAR` and `${VAR}` in string values are replaced with the value
+// of the environment variable and `$$` is an escaped `$`.
+func ParseDataSourceProvisioning(data []byte) (*DataSourceProvisioning, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse provisioning YAML")
+	}
+
+	// Go through JSON so that interpolated values and the untyped jsonData
+	// maps decode with the same rules as API responses.
+	data, err := json.Marshal(interpolateProvisioningValue(raw))
+	if err != nil {
+		return nil, err
+	}
+	file := provisioningFile{}
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "Invalid provisioning file")
+	}
+
+	result := &DataSourceProvisioning{
+		APIVersion:        file.APIVersion,
+		DeleteDataSources: file.DeleteDataSources,
+		DataSources:       make([]DataSource, 0, len(file.DataSources)),
+	}
+	for _, p := range file.DataSources {
+		if p.Name == "" {
+			return nil, errors.New("Invalid provisioning file: datasource without name")
+		}
+		ds := DataSource{
+			Name:              p.Name,
+			Uid:               p.Uid,
+			Type:              p.Type,
+			URL:               p.URL,
+			Access:            p.Access,
+			Database:          p.Database,
+			User:              p.User,
+			Password:          p.Password,
+			OrgId:             p.OrgId,
+			IsDefault:         p.IsDefault,
+			BasicAuth:         p.BasicAuth,
+			BasicAuthUser:     p.BasicAuthUser,
+			BasicAuthPassword: p.BasicAuthPassword,
+			WithCredentials:   p.WithCredentials,
+			Editable:          p.Editable,
+			Version:           p.Version,
+		}
+		if err = convertJSON(p.JSONData, &ds.JSONData); err != nil {
+			return nil, errors.Wrapf(err, "Invalid jsonData for datasource %q", p.Name)
+		}
+		if err = convertJSON(p.SecureJSONData, &ds.SecureJSONData); err != nil {
+			return nil, errors.Wrapf(err, "Invalid secureJsonData for datasource %q", p.Name)
+		}
+		result.DataSources = append(result.DataSources, ds)
+	}
+	return result, nil
+}
+
+// RenderDataSourceProvisioning renders datasources in the provisioning file
+// format. Ids are dropped since provisioning identifies datasources by name.
+func RenderDataSourceProvisioning(p *DataSourceProvisioning) ([]byte, error) {
+	file := provisioningFile{
+		APIVersion:        p.APIVersion,
+		DeleteDataSources: p.DeleteDataSources,
+		DataSources:       make([]provisionedDataSource, 0, len(p.DataSources)),
+	}
+	if file.APIVersion == 0 {
+		file.APIVersion = 1
+	}
+	for _, ds := range p.DataSources {
+		entry := provisionedDataSource{
+			OrgId:             ds.OrgId,
+			Name:              ds.Name,
+			Type:              ds.Type,
+			Access:            ds.Access,
+			Uid:               ds.Uid,
+			URL:               ds.URL,
+			User:              ds.User,
+			Password:          ds.Password,
+			Database:          ds.Database,
+			BasicAuth:         ds.BasicAuth,
+			BasicAuthUser:     ds.BasicAuthUser,
+			BasicAuthPassword: ds.BasicAuthPassword,
+			IsDefault:         ds.IsDefault,
+			WithCredentials:   ds.WithCredentials,
+			Editable:          ds.Editable,
+			Version:           ds.Version,
+		}
+		if err := convertJSON(ds.JSONData, &entry.JSONData); err != nil {
+			return nil, err
+		}
+		if err := convertJSON(ds.SecureJSONData, &entry.SecureJSONData); err != nil {
+			return nil, err
+		}
+		file.DataSources = append(file.DataSources, entry)
+	}
+	return yaml.Marshal(file)
+}
+
+// convertJSON copies from into to through their JSON representation.
+func convertJSON(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// interpolateProvisioningValue expands environment variables in every string
+// of a decoded YAML document and turns YAML maps into JSON compatible ones.
+func interpolateProvisioningValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		parts := strings.Split(v, "$$")
+		for i, part := range parts {
+			parts[i] = os.ExpandEnv(part)
+		}
+		return strings.Join(parts, "$")
+	case map[string]interface{}:
+		for k, val := range v {
+			v[k] = interpolateProvisioningValue(val)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = interpolateProvisioningValue(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = interpolateProvisioningValue(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
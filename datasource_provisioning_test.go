@@ -0,0 +1,143 @@
+package gapi
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gobs/pretty"
+)
+
+const provisioningYAML = `
+apiVersion: 1
+
+deleteDatasources:
+  - name: Graphite
+    orgId: 1
+
+datasources:
+  - name: CloudWatch
+    type: cloudwatch
+    access: proxy
+    uid: cw
+    orgId: 1
+    url: ${CW_URL}
+    isDefault: true
+    jsonData:
+      authType: keys
+      defaultRegion: us-east-1
+    secureJsonData:
+      accessKey: $CW_ACCESS_KEY
+      secretKey: "pa$$word"
+`
+
+func TestParseDataSourceProvisioning(t *testing.T) {
+	os.Setenv("CW_URL", "http://cloudwatch")
+	os.Setenv("CW_ACCESS_KEY", "123")
+	defer os.Unsetenv("CW_URL")
+	defer os.Unsetenv("CW_ACCESS_KEY")
+
+	p, err := ParseDataSourceProvisioning([]byte(provisioningYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(p))
+
+	if p.APIVersion != 1 || len(p.DeleteDataSources) != 1 || p.DeleteDataSources[0].Name != "Graphite" {
+		t.Error("Not correctly parsing provisioning header.")
+	}
+	if len(p.DataSources) != 1 {
+		t.Fatal("Length of parsed datasources should be 1")
+	}
+
+	ds := p.DataSources[0]
+	if ds.Name != "CloudWatch" || ds.Uid != "cw" || ds.OrgId != 1 || !ds.IsDefault {
+		t.Error("Not correctly parsing datasource.")
+	}
+	if ds.URL != "http://cloudwatch" || ds.SecureJSONData.AccessKey != "123" {
+		t.Error("Environment variables should be interpolated.")
+	}
+	if ds.SecureJSONData.SecretKey != "pa$word" {
+		t.Errorf("$$ should be unescaped, got %q", ds.SecureJSONData.SecretKey)
+	}
+	if ds.JSONData.AuthType != "keys" || ds.JSONData.DefaultRegion != "us-east-1" {
+		t.Error("Not correctly parsing jsonData.")
+	}
+}
+
+func TestRenderDataSourceProvisioning(t *testing.T) {
+	ds := DataSource{
+		Id:     3,
+		Name:   "CloudWatch",
+		Type:   "cloudwatch",
+		Access: "proxy",
+		JSONData: JSONData{
+			DefaultRegion: "us-east-1",
+		},
+	}
+	data, err := RenderDataSourceProvisioning(&DataSourceProvisioning{DataSources: []DataSource{ds}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(string(data))
+
+	if !strings.Contains(string(data), "apiVersion: 1") {
+		t.Error("apiVersion should default to 1")
+	}
+
+	p, err := ParseDataSourceProvisioning(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds.Id = 0
+	if len(p.DataSources) != 1 || !reflect.DeepEqual(p.DataSources[0], ds) {
+		t.Errorf("Rendered datasource should parse back unchanged, got %s", pretty.PrettyFormat(p.DataSources))
+	}
+}
+
+func TestRenderDataSourceProvisioningExtraFields(t *testing.T) {
+	ds := DataSource{
+		Name:            "Prometheus",
+		Type:            "prometheus",
+		Access:          "proxy",
+		URL:             "http://prometheus:9090",
+		WithCredentials: true,
+		Editable:        true,
+		Version:         4,
+		JSONData: JSONData{
+			Extra: map[string]interface{}{
+				"httpMethod":   "POST",
+				"timeInterval": "30s",
+				"exemplarTraceIdDestinations": []interface{}{
+					map[string]interface{}{"name": "traceID", "datasourceUid": "tempo"},
+				},
+			},
+		},
+		SecureJSONData: SecureJSONData{
+			Extra: map[string]interface{}{"httpHeaderValue1": "Bearer token"},
+		},
+	}
+	data, err := RenderDataSourceProvisioning(&DataSourceProvisioning{DataSources: []DataSource{ds}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(string(data))
+
+	for _, s := range []string{"httpMethod: POST", "editable: true", "withCredentials: true", "version: 4"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("Rendered datasource should contain %q", s)
+		}
+	}
+
+	p, err := ParseDataSourceProvisioning(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.DataSources) != 1 || !reflect.DeepEqual(p.DataSources[0], ds) {
+		t.Errorf("Rendered datasource should parse back unchanged, got %s", pretty.PrettyFormat(p.DataSources))
+	}
+}
@@ -0,0 +1,327 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// QueryRequest is the body of a request to the `/api/ds/query` endpoint.
+// From and To accept anything Grafana understands as a time range, e.g.
+// "now-1h" or a unix timestamp in milliseconds.
+type QueryRequest struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Queries []DataSourceQuery `json:"queries"`
+	Debug   bool              `json:"debug,omitempty"`
+}
+
+// DataSourceRef identifies the datasource a query is run against.
+type DataSourceRef struct {
+	Uid  string `json:"uid"`
+	Type string `json:"type,omitempty"`
+}
+
+// DataSourceQuery is a single query within a QueryRequest. Model holds the
+// datasource specific fields of the query (e.g. `expr` for Prometheus) and is
+// merged into the top level of the query object when marshalled.
+type DataSourceQuery struct {
+	RefID         string
+	DataSource    DataSourceRef
+	IntervalMs    int64
+	MaxDataPoints int64
+	Model         map[string]interface{}
+}
+
+func (q DataSourceQuery) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(q.Model)+4)
+	for k, v := range q.Model {
+		m[k] = v
+	}
+	m["refId"] = q.RefID
+	m["datasource"] = q.DataSource
+	if q.IntervalMs != 0 {
+		m["intervalMs"] = q.IntervalMs
+	}
+	if q.MaxDataPoints != 0 {
+		m["maxDataPoints"] = q.MaxDataPoints
+	}
+	return json.Marshal(m)
+}
+
+// QueryResponse holds the results of a QueryRequest keyed by refId.
+type QueryResponse struct {
+	Results map[string]QueryResult `json:"results"`
+}
+
+type QueryResult struct {
+	Status int         `json:"status,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	Frames []DataFrame `json:"frames"`
+}
+
+// FieldType is the type of the values held by a DataFrameField.
+type FieldType string
+
+const (
+	FieldTypeTime    FieldType = "time"
+	FieldTypeNumber  FieldType = "number"
+	FieldTypeString  FieldType = "string"
+	FieldTypeBoolean FieldType = "boolean"
+	FieldTypeOther   FieldType = "other"
+)
+
+// DataFrame is a decoded Grafana data frame: a set of equal length fields.
+type DataFrame struct {
+	Name   string
+	RefID  string
+	Meta   map[string]interface{}
+	Fields []DataFrameField
+}
+
+// DataFrameField is a single column of a DataFrame. Values are decoded
+// according to Type: time.Time for time fields, float64 for numbers, string
+// and bool for strings and booleans. Null values are nil.
+type DataFrameField struct {
+	Name   string
+	Type   FieldType
+	Labels map[string]string
+	Config map[string]interface{}
+	Values []interface{}
+}
+
+// Len returns the number of values in the field.
+func (f DataFrameField) Len() int {
+	return len(f.Values)
+}
+
+// value returns the i-th value, or nil when i is out of range.
+func (f DataFrameField) value(i int) interface{} {
+	if i < 0 || i >= len(f.Values) {
+		return nil
+	}
+	return f.Values[i]
+}
+
+// Float returns the i-th value as a float64. ok is false when the value is
+// null, not a number or out of range.
+func (f DataFrameField) Float(i int) (v float64, ok bool) {
+	v, ok = f.value(i).(float64)
+	return v, ok
+}
+
+// Time returns the i-th value as a time.Time. ok is false when the value is
+// null, not a time or out of range.
+func (f DataFrameField) Time(i int) (v time.Time, ok bool) {
+	v, ok = f.value(i).(time.Time)
+	return v, ok
+}
+
+// String returns the i-th value as a string. ok is false when the value is
+// null, not a string or out of range.
+func (f DataFrameField) String(i int) (v string, ok bool) {
+	v, ok = f.value(i).(string)
+	return v, ok
+}
+
+type dataFrameJSON struct {
+	Schema struct {
+		Name   string                 `json:"name"`
+		RefID  string                 `json:"refId"`
+		Meta   map[string]interface{} `json:"meta"`
+		Fields []struct {
+			Name   string                 `json:"name"`
+			Type   FieldType              `json:"type"`
+			Labels map[string]string      `json:"labels"`
+			Config map[string]interface{} `json:"config"`
+		} `json:"fields"`
+	} `json:"schema"`
+	Data struct {
+		Values   [][]json.RawMessage `json:"values"`
+		Nanos    [][]int64           `json:"nanos"`
+		Entities []*struct {
+			NaN    []int `json:"NaN"`
+			Inf    []int `json:"Inf"`
+			NegInf []int `json:"NegInf"`
+		} `json:"entities"`
+	} `json:"data"`
+}
+
+func (f *DataFrame) UnmarshalJSON(b []byte) error {
+	raw := dataFrameJSON{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	f.Name = raw.Schema.Name
+	f.RefID = raw.Schema.RefID
+	f.Meta = raw.Schema.Meta
+	f.Fields = make([]DataFrameField, len(raw.Schema.Fields))
+	for i, s := range raw.Schema.Fields {
+		field := DataFrameField{
+			Name:   s.Name,
+			Type:   s.Type,
+			Labels: s.Labels,
+			Config: s.Config,
+		}
+		if i < len(raw.Data.Values) {
+			values, err := decodeFieldValues(s.Type, raw.Data.Values[i])
+			if err != nil {
+				return errors.Wrapf(err, "Failed to decode values of field %q", s.Name)
+			}
+			field.Values = values
+		}
+		if i < len(raw.Data.Nanos) && raw.Data.Nanos[i] != nil {
+			for j, ns := range raw.Data.Nanos[i] {
+				if j >= len(field.Values) {
+					break
+				}
+				if t, ok := field.Values[j].(time.Time); ok {
+					field.Values[j] = t.Add(time.Duration(ns))
+				}
+			}
+		}
+		if i < len(raw.Data.Entities) && raw.Data.Entities[i] != nil {
+			e := raw.Data.Entities[i]
+			set := func(idx []int, v float64) {
+				for _, j := range idx {
+					if j < len(field.Values) {
+						field.Values[j] = v
+					}
+				}
+			}
+			set(e.NaN, math.NaN())
+			set(e.Inf, math.Inf(1))
+			set(e.NegInf, math.Inf(-1))
+		}
+		f.Fields[i] = field
+	}
+	return nil
+}
+
+func decodeFieldValues(t FieldType, raw []json.RawMessage) ([]interface{}, error) {
+	values := make([]interface{}, len(raw))
+	for i, r := range raw {
+		if string(r) == "null" {
+			continue
+		}
+		var err error
+		switch t {
+		case FieldTypeTime:
+			var ms int64
+			err = json.Unmarshal(r, &ms)
+			values[i] = time.Unix(0, ms*int64(time.Millisecond)).UTC()
+		case FieldTypeNumber:
+			var n float64
+			err = json.Unmarshal(r, &n)
+			values[i] = n
+		case FieldTypeString:
+			var s string
+			err = json.Unmarshal(r, &s)
+			values[i] = s
+		case FieldTypeBoolean:
+			var b bool
+			err = json.Unmarshal(r, &b)
+			values[i] = b
+		default:
+			var v interface{}
+			err = json.Unmarshal(r, &v)
+			values[i] = v
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// QueryDataSources runs the queries of q through Grafana and returns the
+// resulting data frames.
+func (c *Client) QueryDataSources(q QueryRequest) (*QueryResponse, error) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshall query JSON")
+	}
+	req, err := c.newRequest("QueryDataSources", "POST", "/api/ds/query", nil, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failing query is reported with a non 200 status code but still
+	// carries the per refId results, so try those before giving up.
+	result := &QueryResponse{}
+	if err = json.Unmarshal(data, &result); err == nil && len(result.Results) > 0 {
+		return result, nil
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		json.Unmarshal(data, &gmsg)
+		return nil, c.explainNotFound(&GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}, CapabilityDataSourceQuery)
+	}
+	return result, err
+}
+
+// DataSourceProxy sends a request to the datasource identified by uid through
+// Grafana's datasource proxy and returns the raw response body. proxyPath may
+// not contain .. segments, which would leave the proxy for other API routes.
+func (c *Client) DataSourceProxy(uid, method, proxyPath string, query url.Values, body []byte) ([]byte, error) {
+	if uid == "." || uid == ".." {
+		return nil, fmt.Errorf("Invalid datasource uid %q", uid)
+	}
+	for _, segment := range strings.Split(proxyPath, "/") {
+		if segment == ".." {
+			return nil, fmt.Errorf("Invalid proxy path %q, .. segments are not allowed", proxyPath)
+		}
+	}
+	path := fmt.Sprintf("/api/datasources/proxy/uid/%s/%s", uid, proxyPath)
+	rawPath := fmt.Sprintf("/api/datasources/proxy/uid/%s/%s", url.PathEscape(uid), (&url.URL{Path: proxyPath}).EscapedPath())
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewBuffer(body)
+	}
+	req, err := c.newRequest("DataSourceProxy", method, path, query, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	// newRequest cleans the path, which drops the trailing slash some
+	// proxied APIs need.
+	req.URL.Path = strings.TrimSuffix(c.baseURL.Path, "/") + path
+	req.URL.RawPath = strings.TrimSuffix(c.baseURL.EscapedPath(), "/") + rawPath
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var gmsg GrafanaErrorMessage
+		if json.Unmarshal(data, &gmsg) != nil || gmsg.Message == "" {
+			gmsg.Message = string(data)
+		}
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	return data, nil
+}
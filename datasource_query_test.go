@@ -0,0 +1,153 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gobs/pretty"
+)
+
+const (
+	queryDataSourcesJSON = `{"results":{"A":{"status":200,"frames":[{"schema":{"refId":"A","meta":{"executedQueryString":"up"},"fields":[{"name":"Time","type":"time","typeInfo":{"frame":"time.Time"}},{"name":"Value","type":"number","typeInfo":{"frame":"float64","nullable":true},"labels":{"job":"grafana"}}]},"data":{"values":[[1600000000000,1600000060000,1600000120000],[1,null,0]],"entities":[null,{"NaN":[2]}]}}]}}}`
+	queryErrorJSON       = `{"results":{"B":{"status":400,"error":"parse error","frames":[]}}}`
+	proxyJSON            = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+)
+
+func TestDataSourceQueryMarshal(t *testing.T) {
+	q := DataSourceQuery{
+		RefID:      "A",
+		DataSource: DataSourceRef{Uid: "prom", Type: "prometheus"},
+		Model:      map[string]interface{}{"expr": "up"},
+	}
+	data, err := json.Marshal(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]interface{}{}
+	json.Unmarshal(data, &got)
+	if got["refId"] != "A" || got["expr"] != "up" {
+		t.Errorf("Unexpected query JSON: %s", data)
+	}
+	if _, ok := got["intervalMs"]; ok {
+		t.Errorf("intervalMs should be omitted when unset: %s", data)
+	}
+}
+
+func TestQueryDataSources(t *testing.T) {
+	server, client := gapiTestTools(200, queryDataSourcesJSON)
+	defer server.Close()
+
+	resp, err := client.QueryDataSources(QueryRequest{
+		From: "now-1h",
+		To:   "now",
+		Queries: []DataSourceQuery{
+			{RefID: "A", DataSource: DataSourceRef{Uid: "prom"}, Model: map[string]interface{}{"expr": "up"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	frames := resp.Results["A"].Frames
+	if len(frames) != 1 || len(frames[0].Fields) != 2 {
+		t.Fatal("Not correctly parsing returned frames.")
+	}
+
+	times, values := frames[0].Fields[0], frames[0].Fields[1]
+	if ts, ok := times.Time(1); !ok || !ts.Equal(time.Unix(1600000060, 0)) {
+		t.Errorf("Not correctly parsing time values: %v", times.Values)
+	}
+	if values.Labels["job"] != "grafana" {
+		t.Errorf("Not correctly parsing field labels: %v", values.Labels)
+	}
+	if v, ok := values.Float(0); !ok || v != 1 {
+		t.Errorf("Not correctly parsing number values: %v", values.Values)
+	}
+	if values.Values[1] != nil {
+		t.Errorf("Null values should be nil, got %v", values.Values[1])
+	}
+	if v, ok := values.Float(2); !ok || !math.IsNaN(v) {
+		t.Errorf("NaN entities should be decoded, got %v", values.Values[2])
+	}
+	if _, ok := values.Float(values.Len()); ok {
+		t.Error("Out of range values should not be ok")
+	}
+	if _, ok := times.Time(-1); ok {
+		t.Error("Out of range values should not be ok")
+	}
+}
+
+func TestQueryDataSourcesError(t *testing.T) {
+	server, client := gapiTestTools(400, queryErrorJSON)
+	defer server.Close()
+
+	resp, err := client.QueryDataSources(QueryRequest{From: "now-1h", To: "now"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Results["B"].Error != "parse error" {
+		t.Error("Query errors should be reported per refId.")
+	}
+}
+
+func TestDataSourceProxy(t *testing.T) {
+	server, client := gapiTestTools(200, proxyJSON)
+	defer server.Close()
+
+	resp, err := client.DataSourceProxy("prom", "GET", "api/v1/query", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp) != proxyJSON {
+		t.Errorf("Unexpected proxy response: %s", resp)
+	}
+}
+
+func TestDataSourceProxyEscaping(t *testing.T) {
+	var requestPath string
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.EscapedPath()
+		fmt.Fprint(w, proxyJSON)
+	}))
+	defer server.Close()
+
+	if _, err := client.DataSourceProxy("a/b", "GET", "api/v1/query", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if requestPath != "/api/datasources/proxy/uid/a%2Fb/api/v1/query" {
+		t.Errorf("The uid should be escaped, got %s", requestPath)
+	}
+
+	requestPath = ""
+	for _, proxyPath := range []string{"../../../admin/users", "api/../../../../admin/users", ".."} {
+		if _, err := client.DataSourceProxy("prom", "DELETE", proxyPath, nil, nil); err == nil {
+			t.Errorf("Expected %s to be rejected", proxyPath)
+		}
+	}
+	if requestPath != "" {
+		t.Errorf("Rejected paths should not be sent, got %s", requestPath)
+	}
+}
+
+func TestDataSourceProxyTrailingSlash(t *testing.T) {
+	var requestPath string
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		fmt.Fprint(w, proxyJSON)
+	}))
+	defer server.Close()
+
+	if _, err := client.DataSourceProxy("es", "GET", "logs/_search/", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if requestPath != "/api/datasources/proxy/uid/es/logs/_search/" {
+		t.Errorf("The trailing slash of the proxied path should be kept, got %s", requestPath)
+	}
+}
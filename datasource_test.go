@@ -1,6 +1,7 @@
 package gapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,14 +13,21 @@ import (
 
 const (
 	createdDataSourceJSON = `{"id":1,"message":"Datasource added", "name": "test_datasource"}`
+	getDataSourceJSON     = `{"id":1,"uid":"cw","orgId":1,"name":"foo","type":"cloudwatch","access":"proxy","url":"http://some-url.com","isDefault":true,"jsonData":{"authType":"keys","defaultRegion":"us-east-1"},"secureJsonFields":{"accessKey":true,"secretKey":true},"version":2}`
 )
 
 func gapiTestTools(code int, body string) (*httptest.Server, *Client) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(code)
 		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, body)
+		fmt.Fprint(w, body)
 	}))
+}
+
+// gapiTestToolsWithHandler is like gapiTestTools for tests that need to answer
+// each request differently.
+func gapiTestToolsWithHandler(handler http.Handler) (*httptest.Server, *Client) {
+	server := httptest.NewServer(handler)
 
 	tr := &http.Transport{
 		Proxy: func(req *http.Request) (*url.URL, error) {
@@ -34,7 +42,7 @@ func gapiTestTools(code int, body string) (*httptest.Server, *Client) {
 		Host:   "my-grafana.com",
 	}
 
-	client := &Client{"my-key", url, httpClient}
+	client := &Client{key: "my-key", baseURL: url, version: &versionCache{}, Client: httpClient}
 
 	return server, client
 }
@@ -72,3 +80,42 @@ func TestNewDataSource(t *testing.T) {
 		t.Error("datasource creation response should return the created datasource ID")
 	}
 }
+
+func TestRotateDataSourceSecrets(t *testing.T) {
+	var body map[string]interface{}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			fmt.Fprint(w, `{"message":"Datasource updated"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"uid":"prom","name":"foo","type":"prometheus","url":"http://some-url.com","jsonData":{"httpMethod":"POST","timeInterval":"30s"},"secureJsonFields":{"accessKey":true,"secretKey":true}}`)
+	}))
+	defer server.Close()
+
+	fields, err := client.RotateDataSourceSecrets(1, DataSourceSecrets{
+		SecureJSONData: SecureJSONData{SecretKey: "789"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(pretty.PrettyFormat(body))
+
+	jsonData, _ := body["jsonData"].(map[string]interface{})
+	if jsonData["httpMethod"] != "POST" || jsonData["timeInterval"] != "30s" {
+		t.Errorf("jsonData should be sent back unchanged, got %v", body["jsonData"])
+	}
+	secrets, _ := body["secureJsonData"].(map[string]interface{})
+	if len(secrets) != 1 || secrets["secretKey"] != "789" {
+		t.Errorf("Only the rotated secret should be sent, got %v", body["secureJsonData"])
+	}
+	if body["url"] != "http://some-url.com" || body["uid"] != "prom" {
+		t.Errorf("The rest of the datasource should be sent back unchanged, got %v", body)
+	}
+	if !fields["accessKey"] || !fields["secretKey"] {
+		t.Error("Not correctly parsing returned secure fields.")
+	}
+}
@@ -0,0 +1,274 @@
+package gapi
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// EnsureAction reports what an Ensure helper had to do to converge a
+// resource to its desired state.
+type EnsureAction string
+
+const (
+	EnsureCreated   EnsureAction = "created"
+	EnsureUpdated   EnsureAction = "updated"
+	EnsureUnchanged EnsureAction = "unchanged"
+)
+
+// ensureRetries is the number of times an update failing with a version
+// mismatch is retried against a freshly read resource.
+const ensureRetries = 3
+
+// isGrafanaStatus reports whether err is a GrafanaError with the given status
+// code.
+func isGrafanaStatus(err error, code int) bool {
+	gerr, ok := errors.Cause(err).(*GrafanaError)
+	return ok && gerr.StatusCode == code
+}
+
+// EnsureFolder makes sure a folder with the title and parent of opts exists.
+// The folder is looked up by uid, or by title within its parent when opts has
+// no uid. An update rejected because the folder changed in the meantime (412)
+// is retried against the latest version.
+func (c *Client) EnsureFolder(opts FolderCreateOpts) (*Folder, EnsureAction, error) {
+	existing, err := c.findFolder(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing == nil {
+		folder, err := c.CreateFolder(&opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return folder, EnsureCreated, nil
+	}
+
+	action := EnsureUnchanged
+	for attempt := 1; existing.Title != opts.Title; attempt++ {
+		folder, err := c.UpdateFolder(&FolderUpdateOpts{
+			Title:   opts.Title,
+			Uid:     existing.Uid,
+			Version: existing.Version,
+		})
+		if err == nil {
+			existing, action = folder, EnsureUpdated
+			break
+		}
+		if !isGrafanaStatus(err, 412) || attempt == ensureRetries {
+			return nil, "", err
+		}
+		existing, err = c.GetFolderByUID(existing.Uid)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if existing.ParentUid != opts.ParentUid {
+		folder, err := c.MoveFolder(existing.Uid, opts.ParentUid)
+		if err != nil {
+			return nil, "", err
+		}
+		existing, action = folder, EnsureUpdated
+	}
+	return existing, action, nil
+}
+
+// findFolder returns the folder matching opts, or nil when there is none.
+func (c *Client) findFolder(opts FolderCreateOpts) (*Folder, error) {
+	if opts.Uid != "" {
+		folder, err := c.GetFolderByUID(opts.Uid)
+		if isGrafanaStatus(err, 404) {
+			return nil, nil
+		}
+		return folder, err
+	}
+
+	folders, err := c.GetFolderChildren(opts.ParentUid)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if folder.Title == opts.Title {
+			if folder.ParentUid == "" {
+				folder.ParentUid = opts.ParentUid
+			}
+			return &folder, nil
+		}
+	}
+	return nil, nil
+}
+
+// EnsureOrg makes sure an organization with the given name exists.
+func (c *Client) EnsureOrg(name string) (Org, EnsureAction, error) {
+	org, err := c.OrgByName(name)
+	if err == nil {
+		return org, EnsureUnchanged, nil
+	}
+	if !isGrafanaStatus(err, 404) {
+		return org, "", err
+	}
+
+	id, err := c.NewOrg(name)
+	if isGrafanaStatus(err, 409) {
+		// Created concurrently since the lookup.
+		org, err = c.OrgByName(name)
+		return org, EnsureUnchanged, err
+	}
+	if err != nil {
+		return org, "", err
+	}
+	return Org{Id: id, Name: name}, EnsureCreated, nil
+}
+
+// EnsureDataSource makes sure the datasource exists with the configuration of
+// ds. It is looked up by uid, or by name when ds has no uid. Only the fields
+// ds sets, i.e. those that are not zero, are compared and updated, and
+// jsonData keys ds does not mention are kept. An update rejected because the
+// datasource changed in the meantime (409 or 412) is retried against the
+// latest version.
+//
+// Secrets cannot be read back from Grafana, so a secret only causes an update
+// when Grafana reports it as unset. Use RotateDataSourceSecrets to change
+// secrets that are already set.
+func (c *Client) EnsureDataSource(ds *DataSource) (*DataSource, EnsureAction, error) {
+	existing, err := c.findDataSource(ds)
+	if isGrafanaStatus(err, 404) {
+		id, err := c.NewDataSource(ds)
+		if err == nil {
+			created, err := c.DataSource(id)
+			if err != nil {
+				return nil, "", err
+			}
+			return created, EnsureCreated, nil
+		}
+		if !isGrafanaStatus(err, 409) {
+			return nil, "", err
+		}
+		// Created concurrently since the lookup.
+		existing, err = c.findDataSource(ds)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	for attempt := 1; ; attempt++ {
+		merged, changed, err := mergeDataSource(existing, ds)
+		if err != nil {
+			return nil, "", err
+		}
+		if !changed && !dataSourceSecretsUnset(existing, ds) {
+			return existing, EnsureUnchanged, nil
+		}
+		err = c.UpdateDataSource(merged)
+		if err == nil {
+			break
+		}
+		if !(isGrafanaStatus(err, 409) || isGrafanaStatus(err, 412)) || attempt == ensureRetries {
+			return nil, "", err
+		}
+		existing, err = c.DataSource(existing.Id)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	updated, err := c.DataSource(existing.Id)
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, EnsureUpdated, nil
+}
+
+// findDataSource looks up the datasource matching ds by uid, or by name when
+// ds has no uid.
+func (c *Client) findDataSource(ds *DataSource) (*DataSource, error) {
+	if ds.Uid != "" {
+		return c.DataSourceByUID(ds.Uid)
+	}
+	return c.DataSourceByName(ds.Name)
+}
+
+// dataSourceSecretsUnset reports whether desired sets a secret Grafana reports
+// as unset for existing.
+func dataSourceSecretsUnset(existing, desired *DataSource) bool {
+	if desired.Password != "" && !existing.SecureJSONFields["password"] {
+		return true
+	}
+	if desired.BasicAuthPassword != "" && !existing.SecureJSONFields["basicAuthPassword"] {
+		return true
+	}
+	secrets := map[string]interface{}{}
+	convertJSON(desired.SecureJSONData, &secrets)
+	for key := range secrets {
+		if !existing.SecureJSONFields[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeDataSource returns existing with the fields desired sets replaced, and
+// whether any of them differs. jsonData is merged key by key. Secrets are
+// copied but not compared since Grafana does not return them, and the id and
+// version are those of existing.
+func mergeDataSource(existing, desired *DataSource) (*DataSource, bool, error) {
+	base := map[string]interface{}{}
+	if err := convertJSON(existing, &base); err != nil {
+		return nil, false, err
+	}
+	overlay := map[string]interface{}{}
+	if err := convertJSON(desired, &overlay); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for key, value := range overlay {
+		switch {
+		case isZeroJSON(value) || key == "id" || key == "version" || key == "secureJsonFields":
+		case key == "password" || key == "basicAuthPassword" || key == "secureJsonData":
+			base[key] = value
+		case key == "jsonData":
+			jsonData, _ := base[key].(map[string]interface{})
+			if jsonData == nil {
+				jsonData = map[string]interface{}{}
+			}
+			for k, v := range value.(map[string]interface{}) {
+				if !reflect.DeepEqual(jsonData[k], v) {
+					jsonData[k] = v
+					changed = true
+				}
+			}
+			base[key] = jsonData
+		case !reflect.DeepEqual(base[key], value):
+			base[key] = value
+			changed = true
+		}
+	}
+
+	merged := &DataSource{}
+	if err := convertJSON(base, merged); err != nil {
+		return nil, false, err
+	}
+	merged.SecureJSONFields = nil
+	return merged, changed, nil
+}
+
+// isZeroJSON reports whether a decoded JSON value is the zero value of its
+// type.
+func isZeroJSON(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
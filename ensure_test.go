@@ -0,0 +1,185 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestEnsureFolderCreated(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders/ops":
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Folder not found"}`)
+		case "POST /api/folders":
+			fmt.Fprint(w, `{"id":1,"uid":"ops","title":"Ops","version":1}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	folder, action, err := client.EnsureFolder(FolderCreateOpts{Uid: "ops", Title: "Ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != EnsureCreated || folder.Uid != "ops" {
+		t.Errorf("Expected folder to be created, got %s", action)
+	}
+}
+
+func TestEnsureFolderVersionMismatch(t *testing.T) {
+	version, updates := 1, 0
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/folders/ops":
+			fmt.Fprintf(w, `{"id":1,"uid":"ops","title":"Old","version":%d}`, version)
+			// Someone else updates the folder right after the first read.
+			version = 2
+		case "PUT /api/folders/ops":
+			updates++
+			opts := FolderUpdateOpts{}
+			json.NewDecoder(r.Body).Decode(&opts)
+			if opts.Version != version {
+				w.WriteHeader(412)
+				fmt.Fprint(w, `{"message":"the folder has been changed by someone else","status":"version-mismatch"}`)
+				return
+			}
+			fmt.Fprint(w, `{"id":1,"uid":"ops","title":"Ops","version":3}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	folder, action, err := client.EnsureFolder(FolderCreateOpts{Uid: "ops", Title: "Ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != EnsureUpdated || folder.Version != 3 || updates != 2 {
+		t.Errorf("Expected update to be retried, got %s after %d updates", action, updates)
+	}
+}
+
+func TestEnsureOrg(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/orgs/name/Ops":
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Organization not found"}`)
+		case "POST /api/orgs":
+			fmt.Fprint(w, createdOrgJSON)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	org, action, err := client.EnsureOrg("Ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != EnsureCreated || org.Id != 1 || org.Name != "Ops" {
+		t.Errorf("Expected org to be created, got %s %v", action, org)
+	}
+}
+
+func TestEnsureDataSource(t *testing.T) {
+	updated := false
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/uid/cw", "GET /api/datasources/1":
+			if updated {
+				fmt.Fprint(w, `{"id":1,"uid":"cw","orgId":1,"name":"foo","type":"cloudwatch","access":"proxy","url":"http://other-url.com","isDefault":true,"jsonData":{"authType":"keys","defaultRegion":"us-east-1"},"secureJsonFields":{"accessKey":true,"secretKey":true}}`)
+				return
+			}
+			fmt.Fprint(w, getDataSourceJSON)
+		case "PUT /api/datasources/1":
+			updated = true
+			fmt.Fprint(w, `{"message":"Datasource updated"}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ds := &DataSource{
+		Uid:       "cw",
+		Name:      "foo",
+		Type:      "cloudwatch",
+		Access:    "proxy",
+		URL:       "http://some-url.com",
+		IsDefault: true,
+		JSONData: JSONData{
+			AuthType:      "keys",
+			DefaultRegion: "us-east-1",
+		},
+		SecureJSONData: SecureJSONData{
+			AccessKey: "123",
+		},
+	}
+
+	_, action, err := client.EnsureDataSource(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != EnsureUnchanged {
+		t.Errorf("Expected datasource to be unchanged, got %s", action)
+	}
+
+	ds.URL = "http://other-url.com"
+	result, action, err := client.EnsureDataSource(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != EnsureUpdated || result.URL != ds.URL {
+		t.Errorf("Expected datasource to be updated, got %s", action)
+	}
+}
+
+func TestEnsureDataSourceMerge(t *testing.T) {
+	puts := 0
+	var body map[string]interface{}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/datasources/name/prom", "GET /api/datasources/1":
+			fmt.Fprint(w, `{"id":1,"uid":"prom","orgId":1,"name":"prom","type":"prometheus","access":"proxy","url":"http://prometheus:9090","basicAuth":true,"jsonData":{"httpMethod":"POST","timeInterval":"30s"},"version":3}`)
+		case "PUT /api/datasources/1":
+			puts++
+			if puts == 1 {
+				w.WriteHeader(409)
+				fmt.Fprint(w, `{"message":"Datasource has already been updated by someone else. Please reload and try again"}`)
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			fmt.Fprint(w, `{"message":"Datasource updated"}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	ds := &DataSource{Name: "prom", Type: "prometheus"}
+	if _, action, err := client.EnsureDataSource(ds); err != nil || action != EnsureUnchanged {
+		t.Errorf("Fields not set should not be compared, got %s %v", action, err)
+	}
+
+	ds.JSONData.Extra = map[string]interface{}{"timeInterval": "15s"}
+	if _, action, err := client.EnsureDataSource(ds); err != nil || action != EnsureUpdated {
+		t.Fatalf("Expected datasource to be updated, got %s %v", action, err)
+	}
+	if puts != 2 {
+		t.Errorf("Expected the conflicting update to be retried, got %d updates", puts)
+	}
+	jsonData, _ := body["jsonData"].(map[string]interface{})
+	if jsonData["httpMethod"] != "POST" || jsonData["timeInterval"] != "15s" {
+		t.Errorf("jsonData should be merged, got %v", body["jsonData"])
+	}
+	if body["basicAuth"] != true || body["url"] != "http://prometheus:9090" || body["version"] != 3.0 {
+		t.Errorf("Fields not set should be kept, got %v", body)
+	}
+}
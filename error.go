@@ -6,7 +6,7 @@ import "fmt"
 // On a 412 Error, an additional Status field may be present explainin
 type GrafanaErrorMessage struct {
 	Message string `json:"message"`
-	Status  string `json:status, omitempty`
+	Status  string `json:"status,omitempty"`
 }
 
 func (gem GrafanaErrorMessage) String() string {
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"io/ioutil"
@@ -25,23 +27,67 @@ type Folder struct {
 	UpdatedBy string    `json:"updatedBy"`
 	Updated   time.Time `json:"updated"`
 	Version   int       `json:"version"`
+	ParentUid string    `json:"parentUid,omitempty"`
+	// Parents lists the ancestors of a nested folder, root first. It is only
+	// returned when fetching a single folder.
+	Parents []Folder `json:"parents,omitempty"`
 }
 
 type FolderCreateOpts struct {
-	Title string `json:"title"`
-	Uid   string `json:"uid"`
+	Title     string `json:"title"`
+	Uid       string `json:"uid"`
+	ParentUid string `json:"parentUid,omitempty"`
 }
 
 type FolderUpdateOpts struct {
 	Title     string `json:"title"`
 	Uid       string `json:"uid"`
-	Version   int    `json:"version, omitempty"`
+	Version   int    `json:"version,omitempty"`
 	Overwrite bool   `json:"overwrite"`
 }
 
+// FolderNode is a folder within the tree built by GetFolderTree.
+type FolderNode struct {
+	Folder     Folder
+	Dashboards int
+	Children   []*FolderNode
+}
+
+// TotalDashboards returns the number of dashboards in the folder and all of
+// its descendants.
+func (n *FolderNode) TotalDashboards() int {
+	total := n.Dashboards
+	for _, child := range n.Children {
+		total += child.TotalDashboards()
+	}
+	return total
+}
+
+// Walk calls fn for the node and each of its descendants, depth first. The
+// node itself has depth 0. Walking stops at the first error returned by fn.
+func (n *FolderNode) Walk(fn func(node *FolderNode, depth int) error) error {
+	return n.walk(fn, 0)
+}
+
+func (n *FolderNode) walk(fn func(node *FolderNode, depth int) error, depth int) error {
+	if err := fn(n, depth); err != nil {
+		return err
+	}
+	for _, child := range n.Children {
+		if err := child.walk(fn, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// folderPageSize is the number of folders or dashboards requested per page
+// when listing all of them.
+const folderPageSize = 1000
+
 func (c *Client) GetAllFolders() ([]Folder, error) {
 	folders := make([]Folder, 0)
-	req, err := c.newRequest("GET", "/api/folders/", nil, nil)
+	req, err := c.newRequest("GetAllFolders", "GET", "/api/folders/", nil, nil)
 	if err != nil {
 		return folders, err
 	}
@@ -72,7 +118,7 @@ func (c *Client) GetAllFolders() ([]Folder, error) {
 
 func (c *Client) GetFolderByUID(uid string) (*Folder, error) {
 	path := fmt.Sprintf("/api/folders/%s", uid)
-	req, err := c.newRequest("GET", path, nil, nil)
+	req, err := c.newRequest("GetFolderByUID", "GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +147,7 @@ func (c *Client) GetFolderByUID(uid string) (*Folder, error) {
 
 func (c *Client) GetFolderByID(id int) (*Folder, error) {
 	path := fmt.Sprintf("/api/folders/id/%d", id)
-	req, err := c.newRequest("GET", path, nil, nil)
+	req, err := c.newRequest("GetFolderByID", "GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +179,7 @@ func (c *Client) CreateFolder(folder *FolderCreateOpts) (*Folder, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to marshall folder JSON")
 	}
-	req, err := c.newRequest("POST", "/api/folders", nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("CreateFolder", "POST", "/api/folders", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +212,7 @@ func (c *Client) UpdateFolder(folder *FolderUpdateOpts) (*Folder, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to marshall folder JSON")
 	}
-	req, err := c.newRequest("PUT", path, nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("UpdateFolder", "PUT", path, nil, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -195,7 +241,7 @@ func (c *Client) UpdateFolder(folder *FolderUpdateOpts) (*Folder, error) {
 
 func (c *Client) DeleteFolderByUID(uid string) error {
 	path := fmt.Sprintf("/api/folders/%s", uid)
-	req, err := c.newRequest("DELETE", path, nil, nil)
+	req, err := c.newRequest("DeleteFolderByUID", "DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -214,3 +260,217 @@ func (c *Client) DeleteFolderByUID(uid string) error {
 
 	return nil
 }
+
+// GetFolderChildren returns the direct subfolders of the folder with the given
+// uid. An empty parentUid returns the top level folders.
+func (c *Client) GetFolderChildren(parentUid string) ([]Folder, error) {
+	folders := make([]Folder, 0)
+	for page := 1; ; page++ {
+		query := url.Values{}
+		if parentUid != "" {
+			query.Add("parentUid", parentUid)
+		}
+		query.Add("limit", strconv.Itoa(folderPageSize))
+		query.Add("page", strconv.Itoa(page))
+		req, err := c.newRequest("GetFolderChildren", "GET", "/api/folders", query, nil)
+		if err != nil {
+			return folders, err
+		}
+
+		resp, err := c.Do(req)
+		if err != nil {
+			return folders, errors.Wrap(err, "Unable to perform HTTP request")
+		}
+
+		if resp.StatusCode != 200 {
+			var gmsg GrafanaErrorMessage
+			dec := json.NewDecoder(resp.Body)
+			dec.Decode(&gmsg)
+			return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+		}
+
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return folders, err
+		}
+
+		result := make([]Folder, 0)
+		err = json.Unmarshal(data, &result)
+		if err != nil {
+			return folders, err
+		}
+		folders = append(folders, result...)
+		if len(result) < folderPageSize {
+			return folders, nil
+		}
+	}
+}
+
+// GetFolderAncestors returns the ancestors of the folder with the given uid,
+// starting with the top level folder. It is empty for a top level folder.
+func (c *Client) GetFolderAncestors(uid string) ([]Folder, error) {
+	folder, err := c.GetFolderByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(folder.Parents) > 0 {
+		return folder.Parents, nil
+	}
+
+	// Older versions do not return parents, follow the parentUid chain.
+	ancestors := make([]Folder, 0)
+	visited := map[string]bool{folder.Uid: true}
+	for folder.ParentUid != "" {
+		if visited[folder.ParentUid] {
+			return nil, fmt.Errorf("Folder %s is its own ancestor", folder.ParentUid)
+		}
+		visited[folder.ParentUid] = true
+		folder, err = c.GetFolderByUID(folder.ParentUid)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append([]Folder{*folder}, ancestors...)
+	}
+	return ancestors, nil
+}
+
+// MoveFolder moves the folder with the given uid under parentUid. An empty
+// parentUid moves it to the top level.
+func (c *Client) MoveFolder(uid, parentUid string) (*Folder, error) {
+	path := fmt.Sprintf("/api/folders/%s/move", uid)
+	data, err := json.Marshal(map[string]string{
+		"parentUid": parentUid,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshall folder JSON")
+	}
+	req, err := c.newRequest("MoveFolder", "POST", path, nil, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return nil, c.explainNotFound(&GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}, CapabilityNestedFolders)
+	}
+
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Folder{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// generalFolderUid is the uid the search API accepts for the General folder,
+// which holds the dashboards outside any folder.
+const generalFolderUid = "general"
+
+// GetFolderTree returns the full folder hierarchy, one node per top level
+// folder, with the number of dashboards directly in each folder. The first
+// node is the General folder, which has no children and counts the
+// dashboards outside any folder. It requires nested folders support, since
+// older versions ignore the parent when listing folders.
+func (c *Client) GetFolderTree() ([]*FolderNode, error) {
+	if err := c.RequireCapability(CapabilityNestedFolders); err != nil {
+		return nil, err
+	}
+	general := &FolderNode{Folder: Folder{Uid: generalFolderUid, Title: "General"}}
+	var err error
+	general.Dashboards, err = c.folderDashboardCount(generalFolderUid)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.folderNodes("", map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return append([]*FolderNode{general}, nodes...), nil
+}
+
+// folderNodes builds the subtrees of the children of parentUid. Folders
+// already visited are skipped, so that listings including the parent or an
+// ancestor are not walked forever.
+func (c *Client) folderNodes(parentUid string, visited map[string]bool) ([]*FolderNode, error) {
+	folders, err := c.GetFolderChildren(parentUid)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*FolderNode, 0, len(folders))
+	for _, folder := range folders {
+		// Listings may leave out the parent of the folders they list.
+		if folder.ParentUid == "" {
+			folder.ParentUid = parentUid
+		}
+		if folder.ParentUid != parentUid || visited[folder.Uid] {
+			continue
+		}
+		visited[folder.Uid] = true
+		node := &FolderNode{Folder: folder}
+		node.Dashboards, err = c.folderDashboardCount(folder.Uid)
+		if err != nil {
+			return nil, err
+		}
+		node.Children, err = c.folderNodes(folder.Uid, visited)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// folderDashboardCount counts the dashboards directly in a folder using the
+// search API.
+func (c *Client) folderDashboardCount(uid string) (int, error) {
+	count := 0
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Add("type", "dash-db")
+		query.Add("folderUIDs", uid)
+		query.Add("limit", strconv.Itoa(folderPageSize))
+		query.Add("page", strconv.Itoa(page))
+		req, err := c.newRequest("GetFolderTree", "GET", "/api/search", query, nil)
+		if err != nil {
+			return count, err
+		}
+
+		resp, err := c.Do(req)
+		if err != nil {
+			return count, errors.Wrap(err, "Unable to perform HTTP request")
+		}
+
+		if resp.StatusCode != 200 {
+			var gmsg GrafanaErrorMessage
+			dec := json.NewDecoder(resp.Body)
+			dec.Decode(&gmsg)
+			return count, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+		}
+
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return count, err
+		}
+
+		result := make([]struct {
+			Uid string `json:"uid"`
+		}, 0)
+		err = json.Unmarshal(data, &result)
+		if err != nil {
+			return count, err
+		}
+		count += len(result)
+		if len(result) < folderPageSize {
+			return count, nil
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gobs/pretty"
+	"github.com/pkg/errors"
+)
+
+const (
+	getFolderChildrenJSON = `[{"id":2,"uid":"child","title":"Child","parentUid":"parent"}]`
+	getNestedFolderJSON   = `{"id":3,"uid":"grandchild","title":"Grandchild","parentUid":"child","parents":[{"id":1,"uid":"parent","title":"Parent"},{"id":2,"uid":"child","title":"Child","parentUid":"parent"}]}`
+	moveFolderJSON        = `{"id":3,"uid":"grandchild","title":"Grandchild","parentUid":"parent","version":2}`
+)
+
+func TestGetFolderChildren(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("parentUid") != "parent" {
+			t.Errorf("Unexpected parentUid query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, getFolderChildrenJSON)
+	}))
+	defer server.Close()
+
+	folders, err := client.GetFolderChildren("parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(folders))
+
+	if len(folders) != 1 || folders[0].Uid != "child" || folders[0].ParentUid != "parent" {
+		t.Error("Not correctly parsing returned folders.")
+	}
+}
+
+func TestGetFolderAncestors(t *testing.T) {
+	server, client := gapiTestTools(200, getNestedFolderJSON)
+	defer server.Close()
+
+	ancestors, err := client.GetFolderAncestors("grandchild")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ancestors) != 2 || ancestors[0].Uid != "parent" || ancestors[1].Uid != "child" {
+		t.Errorf("Ancestors should be listed root first, got %s", pretty.PrettyFormat(ancestors))
+	}
+}
+
+func TestMoveFolder(t *testing.T) {
+	server, client := gapiTestTools(200, moveFolderJSON)
+	defer server.Close()
+
+	folder, err := client.MoveFolder("grandchild", "parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.ParentUid != "parent" {
+		t.Error("Not correctly parsing moved folder.")
+	}
+}
+
+func TestGetFolderTree(t *testing.T) {
+	folders := map[string]string{
+		"":       `[{"id":1,"uid":"parent","title":"Parent"}]`,
+		"parent": `[{"id":2,"uid":"child","title":"Child","parentUid":"parent"}]`,
+		"child":  `[{"id":2,"uid":"child","title":"Child","parentUid":"parent"}]`,
+	}
+	dashboards := map[string]string{
+		"general": `[{"uid":"d"}]`,
+		"parent":  `[{"uid":"a"},{"uid":"b"}]`,
+		"child":   `[{"uid":"c"}]`,
+	}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			fmt.Fprint(w, `{"database":"ok","version":"10.2.0"}`)
+		case "/api/folders":
+			fmt.Fprint(w, folders[r.URL.Query().Get("parentUid")])
+		case "/api/search":
+			fmt.Fprint(w, dashboards[r.URL.Query().Get("folderUIDs")])
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	tree, err := client.GetFolderTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(tree))
+
+	if len(tree) != 2 || tree[0].Folder.Uid != "general" || tree[0].Dashboards != 1 {
+		t.Fatal("The General folder should come first with its dashboards.")
+	}
+	// The listing of child is not its children and must be ignored.
+	if len(tree[1].Children) != 1 || len(tree[1].Children[0].Children) != 0 {
+		t.Fatal("Not correctly building folder tree.")
+	}
+	if tree[1].Dashboards != 2 || tree[1].TotalDashboards() != 3 {
+		t.Error("Not correctly counting dashboards.")
+	}
+	if tree[1].Children[0].Folder.ParentUid != "parent" {
+		t.Error("Children should reference their parent.")
+	}
+
+	visited := []string{}
+	tree[1].Walk(func(node *FolderNode, depth int) error {
+		visited = append(visited, fmt.Sprintf("%s:%d", node.Folder.Uid, depth))
+		return nil
+	})
+	if fmt.Sprint(visited) != "[parent:0 child:1]" {
+		t.Errorf("Unexpected walk order %v", visited)
+	}
+}
+
+func TestGetFolderTreeWithoutParentUid(t *testing.T) {
+	folders := map[string]string{
+		"":       `[{"id":1,"uid":"parent","title":"Parent"}]`,
+		"parent": `[{"id":2,"uid":"child","title":"Child"}]`,
+		"child":  `[{"id":1,"uid":"parent","title":"Parent"}]`,
+	}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			fmt.Fprint(w, `{"database":"ok","version":"10.2.0"}`)
+		case "/api/folders":
+			fmt.Fprint(w, folders[r.URL.Query().Get("parentUid")])
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	tree, err := client.GetFolderTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The listing of child includes its parent, which must not be walked again.
+	if len(tree) != 2 || len(tree[1].Children) != 1 || len(tree[1].Children[0].Children) != 0 {
+		t.Fatalf("Not correctly building folder tree %s", pretty.PrettyFormat(tree))
+	}
+	if tree[1].Children[0].Folder.ParentUid != "parent" {
+		t.Error("Children should reference their parent.")
+	}
+}
+
+func TestGetFolderAncestorsCycle(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/folders/a":
+			fmt.Fprint(w, `{"id":1,"uid":"a","title":"A","parentUid":"b"}`)
+		case "/api/folders/b":
+			fmt.Fprint(w, `{"id":2,"uid":"b","title":"B","parentUid":"a"}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := client.GetFolderAncestors("a"); err == nil {
+		t.Error("Expected an error for folders that are their own ancestors")
+	}
+}
+
+func TestGetFolderTreeWithoutNestedFolders(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			fmt.Fprint(w, `{"database":"ok","version":"9.5.2"}`)
+		case "/api/folders":
+			// Grafana 9 ignores parentUid and lists every folder.
+			fmt.Fprint(w, `[{"id":1,"uid":"parent","title":"Parent"}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := client.GetFolderTree(); errors.Cause(err) != ErrUnsupportedVersion {
+		t.Errorf("Expected an unsupported version error, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package gapitest
+
+import (
+	"net/http"
+	"sort"
+)
+
+type alertNotification struct {
+	id     int64
+	orgId  int64
+	name   string
+	fields map[string]interface{}
+}
+
+func (a *alertNotification) json() map[string]interface{} {
+	result := make(map[string]interface{}, len(a.fields)+2)
+	for k, v := range a.fields {
+		result[k] = v
+	}
+	result["id"] = a.id
+	result["name"] = a.name
+	return result
+}
+
+func (s *Server) registerAlertNotificationRoutes() {
+	s.handle("GET", "/api/alert-notifications", s.listAlertNotifications)
+	s.handle("POST", "/api/alert-notifications", s.postAlertNotification)
+	s.handle("GET", "/api/alert-notifications/:id", s.getAlertNotification)
+	s.handle("PUT", "/api/alert-notifications/:id", s.putAlertNotification)
+	s.handle("DELETE", "/api/alert-notifications/:id", s.deleteAlertNotification)
+}
+
+func (s *Server) alertNotificationNameTaken(orgId int64, name string, except *alertNotification) bool {
+	for _, a := range s.alertNotifications {
+		if a != except && a.orgId == orgId && a.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// alertNotificationParam returns the notification of the id path parameter
+// in the org of the request. It writes an error when either cannot be found.
+func (s *Server) alertNotificationParam(w http.ResponseWriter, r *http.Request, p params) (*alertNotification, bool) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return nil, false
+	}
+	a, ok := s.alertNotifications[p.int64("id")]
+	if !ok || a.orgId != orgId {
+		writeError(w, http.StatusNotFound, "Alert notification not found")
+		return nil, false
+	}
+	return a, true
+}
+
+func (s *Server) listAlertNotifications(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	notifications := make([]*alertNotification, 0)
+	for _, a := range s.alertNotifications {
+		if a.orgId == orgId {
+			notifications = append(notifications, a)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].id < notifications[j].id })
+	result := make([]map[string]interface{}, 0, len(notifications))
+	for _, a := range notifications {
+		result = append(result, a.json())
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) postAlertNotification(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	fields := map[string]interface{}{}
+	if !readJSON(w, r, &fields) {
+		return
+	}
+	name, _ := fields["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Alert notification name is required")
+		return
+	}
+	if s.alertNotificationNameTaken(orgId, name, nil) {
+		writeError(w, http.StatusConflict, "Alert notification or its uid already exists")
+		return
+	}
+	a := &alertNotification{
+		id:     s.nextID("alertNotification"),
+		orgId:  orgId,
+		name:   name,
+		fields: fields,
+	}
+	s.alertNotifications[a.id] = a
+	writeJSON(w, http.StatusOK, a.json())
+}
+
+func (s *Server) getAlertNotification(w http.ResponseWriter, r *http.Request, p params) {
+	if a, ok := s.alertNotificationParam(w, r, p); ok {
+		writeJSON(w, http.StatusOK, a.json())
+	}
+}
+
+func (s *Server) putAlertNotification(w http.ResponseWriter, r *http.Request, p params) {
+	a, ok := s.alertNotificationParam(w, r, p)
+	if !ok {
+		return
+	}
+	fields := map[string]interface{}{}
+	if !readJSON(w, r, &fields) {
+		return
+	}
+	name, _ := fields["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Alert notification name is required")
+		return
+	}
+	if s.alertNotificationNameTaken(a.orgId, name, a) {
+		writeError(w, http.StatusConflict, "Alert notification or its uid already exists")
+		return
+	}
+	a.name = name
+	a.fields = fields
+	writeJSON(w, http.StatusOK, a.json())
+}
+
+func (s *Server) deleteAlertNotification(w http.ResponseWriter, r *http.Request, p params) {
+	a, ok := s.alertNotificationParam(w, r, p)
+	if !ok {
+		return
+	}
+	delete(s.alertNotifications, a.id)
+	writeError(w, http.StatusOK, "Notification deleted")
+}
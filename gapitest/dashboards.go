@@ -0,0 +1,291 @@
+package gapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type dashboard struct {
+	id       int64
+	orgId    int64
+	uid      string
+	folderId int64
+	model    map[string]interface{}
+	version  int
+	created  time.Time
+	updated  time.Time
+	versions []dashboardVersion
+}
+
+type dashboardVersion struct {
+	version int
+	created time.Time
+	message string
+	data    map[string]interface{}
+}
+
+func (d *dashboard) title() string {
+	title, _ := d.model["title"].(string)
+	return title
+}
+
+func (d *dashboard) tags() []interface{} {
+	tags, _ := d.model["tags"].([]interface{})
+	if tags == nil {
+		tags = []interface{}{}
+	}
+	return tags
+}
+
+func (d *dashboard) url() string {
+	return fmt.Sprintf("/d/%s/%s", d.uid, slugify(d.title()))
+}
+
+func (s *Server) registerDashboardRoutes() {
+	s.handle("POST", "/api/dashboards/db", s.postDashboard)
+	s.handle("GET", "/api/dashboards/uid/:uid", s.getDashboard)
+	s.handle("DELETE", "/api/dashboards/uid/:uid", s.deleteDashboard)
+	s.handle("GET", "/api/dashboards/uid/:uid/versions", s.listDashboardVersions)
+	s.handle("GET", "/api/dashboards/uid/:uid/versions/:version", s.getDashboardVersion)
+}
+
+func (s *Server) dashboardByUID(orgId int64, uid string) *dashboard {
+	for _, d := range s.dashboards {
+		if d.orgId == orgId && d.uid == uid {
+			return d
+		}
+	}
+	return nil
+}
+
+// dashboardUIDParam returns the dashboard of the uid path parameter in the
+// org of the request. It writes an error when either cannot be found.
+func (s *Server) dashboardUIDParam(w http.ResponseWriter, r *http.Request, p params) (*dashboard, bool) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return nil, false
+	}
+	d := s.dashboardByUID(orgId, p["uid"])
+	if d == nil {
+		writeError(w, http.StatusNotFound, "Dashboard not found")
+		return nil, false
+	}
+	return d, true
+}
+
+// postDashboard creates or updates a dashboard. Like Grafana, it rejects
+// updates made against another version than the current one and dashboards
+// with the title of another dashboard of the folder with a 412, unless
+// overwrite is set.
+func (s *Server) postDashboard(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	body := struct {
+		Dashboard map[string]interface{} `json:"dashboard"`
+		FolderId  int64                  `json:"folderId"`
+		FolderUid string                 `json:"folderUid"`
+		Overwrite bool                   `json:"overwrite"`
+		Message   string                 `json:"message"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	model := body.Dashboard
+	if model == nil {
+		writeError(w, http.StatusBadRequest, "Dashboard is required")
+		return
+	}
+	title, _ := model["title"].(string)
+	if strings.TrimSpace(title) == "" {
+		writeError(w, http.StatusBadRequest, "Dashboard title cannot be empty")
+		return
+	}
+
+	folderId := body.FolderId
+	if body.FolderUid != "" {
+		f := s.folderByUID(orgId, body.FolderUid)
+		if f == nil {
+			writeError(w, http.StatusBadRequest, "Folder not found")
+			return
+		}
+		folderId = f.id
+	} else if f, ok := s.folders[folderId]; folderId != 0 && (!ok || f.orgId != orgId) {
+		writeError(w, http.StatusBadRequest, "Folder not found")
+		return
+	}
+
+	uid, _ := model["uid"].(string)
+	var existing *dashboard
+	if uid != "" {
+		existing = s.dashboardByUID(orgId, uid)
+		if existing == nil && s.folderByUID(orgId, uid) != nil {
+			writeError(w, http.StatusBadRequest, "A folder with the same uid already exists")
+			return
+		}
+	} else if id, _ := model["id"].(float64); id != 0 {
+		existing, ok = s.dashboards[int64(id)]
+		if !ok || existing.orgId != orgId {
+			writeError(w, http.StatusNotFound, "Dashboard not found")
+			return
+		}
+	}
+
+	if existing != nil && !body.Overwrite {
+		version, _ := model["version"].(float64)
+		if int(version) != existing.version {
+			writeVersionMismatch(w, "The dashboard has been changed by someone else")
+			return
+		}
+	}
+	for id, other := range s.dashboards {
+		if other == existing || other.orgId != orgId || other.folderId != folderId || !strings.EqualFold(other.title(), title) {
+			continue
+		}
+		if !body.Overwrite {
+			writeJSON(w, http.StatusPreconditionFailed, map[string]string{
+				"message": "A dashboard with the same name in the folder already exists",
+				"status":  "name-exists",
+			})
+			return
+		}
+		delete(s.dashboards, id)
+	}
+
+	now := s.now()
+	d := existing
+	if d == nil {
+		if uid == "" {
+			uid = s.newUID()
+		}
+		d = &dashboard{
+			id:      s.nextID("dashboard"),
+			orgId:   orgId,
+			uid:     uid,
+			created: now,
+		}
+		s.dashboards[d.id] = d
+	}
+	d.folderId = folderId
+	d.version++
+	d.updated = now
+	model["id"] = d.id
+	model["uid"] = d.uid
+	model["version"] = d.version
+	d.model = model
+	d.versions = append(d.versions, dashboardVersion{
+		version: d.version,
+		created: now,
+		message: body.Message,
+		data:    model,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      d.id,
+		"uid":     d.uid,
+		"url":     d.url(),
+		"slug":    slugify(title),
+		"status":  "success",
+		"version": d.version,
+	})
+}
+
+func (s *Server) getDashboard(w http.ResponseWriter, r *http.Request, p params) {
+	d, ok := s.dashboardUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	meta := map[string]interface{}{
+		"type":        "db",
+		"canSave":     true,
+		"canEdit":     true,
+		"canAdmin":    true,
+		"canStar":     true,
+		"slug":        slugify(d.title()),
+		"url":         d.url(),
+		"created":     d.created,
+		"updated":     d.updated,
+		"updatedBy":   "admin",
+		"createdBy":   "admin",
+		"version":     d.version,
+		"hasAcl":      false,
+		"isFolder":    false,
+		"folderId":    0,
+		"folderTitle": "General",
+		"folderUrl":   "",
+		"provisioned": false,
+	}
+	if f := s.folders[d.folderId]; f != nil {
+		meta["folderId"] = f.id
+		meta["folderUid"] = f.uid
+		meta["folderTitle"] = f.title
+		meta["folderUrl"] = f.url()
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"meta":      meta,
+		"dashboard": d.model,
+	})
+}
+
+func (s *Server) deleteDashboard(w http.ResponseWriter, r *http.Request, p params) {
+	d, ok := s.dashboardUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	delete(s.dashboards, d.id)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"title":   d.title(),
+		"message": fmt.Sprintf("Dashboard %s deleted", d.title()),
+		"id":      d.id,
+	})
+}
+
+func versionJSON(d *dashboard, v dashboardVersion) map[string]interface{} {
+	parentVersion := v.version - 1
+	return map[string]interface{}{
+		"id":            v.version,
+		"dashboardId":   d.id,
+		"uid":           d.uid,
+		"parentVersion": parentVersion,
+		"restoredFrom":  0,
+		"version":       v.version,
+		"created":       v.created,
+		"createdBy":     "admin",
+		"message":       v.message,
+	}
+}
+
+// listDashboardVersions lists the versions of a dashboard, newest first.
+func (s *Server) listDashboardVersions(w http.ResponseWriter, r *http.Request, p params) {
+	d, ok := s.dashboardUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	versions := make([]map[string]interface{}, 0, len(d.versions))
+	for i := len(d.versions) - 1; i >= 0; i-- {
+		versions = append(versions, versionJSON(d, d.versions[i]))
+	}
+	start, end := paginate(r, len(versions), len(versions)+1)
+	writeJSON(w, http.StatusOK, versions[start:end])
+}
+
+func (s *Server) getDashboardVersion(w http.ResponseWriter, r *http.Request, p params) {
+	d, ok := s.dashboardUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	version, _ := strconv.Atoi(p["version"])
+	for _, v := range d.versions {
+		if v.version == version {
+			result := versionJSON(d, v)
+			result["data"] = v.data
+			writeJSON(w, http.StatusOK, result)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Dashboard version not found")
+}
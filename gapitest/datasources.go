@@ -0,0 +1,222 @@
+package gapitest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// datasource keeps the fields sent by clients as is, apart from the secure
+// JSON data which is never returned.
+type datasource struct {
+	id      int64
+	orgId   int64
+	uid     string
+	name    string
+	version int64
+	fields  map[string]interface{}
+	secure  map[string]interface{}
+}
+
+func (ds *datasource) json() map[string]interface{} {
+	result := make(map[string]interface{}, len(ds.fields)+6)
+	for k, v := range ds.fields {
+		result[k] = v
+	}
+	secureFields := make(map[string]bool, len(ds.secure))
+	for k := range ds.secure {
+		secureFields[k] = true
+	}
+	delete(result, "secureJsonData")
+	result["id"] = ds.id
+	result["uid"] = ds.uid
+	result["orgId"] = ds.orgId
+	result["name"] = ds.name
+	result["version"] = ds.version
+	result["secureJsonFields"] = secureFields
+	return result
+}
+
+func (s *Server) registerDataSourceRoutes() {
+	s.handle("GET", "/api/datasources", s.listDataSources)
+	s.handle("POST", "/api/datasources", s.postDataSource)
+	s.handle("GET", "/api/datasources/uid/:uid", s.getDataSourceBy("uid"))
+	s.handle("GET", "/api/datasources/name/:name", s.getDataSourceBy("name"))
+	s.handle("GET", "/api/datasources/:id", s.getDataSourceBy("id"))
+	s.handle("PUT", "/api/datasources/:id", s.putDataSource)
+	s.handle("DELETE", "/api/datasources/:id", s.deleteDataSource)
+}
+
+// findDataSource returns the datasource of the org whose id, uid or name is
+// value.
+func (s *Server) findDataSource(orgId int64, by, value string) *datasource {
+	for _, ds := range s.datasources {
+		if ds.orgId != orgId {
+			continue
+		}
+		switch {
+		case by == "uid" && ds.uid == value,
+			by == "name" && ds.name == value,
+			by == "id" && strconv.FormatInt(ds.id, 10) == value:
+			return ds
+		}
+	}
+	return nil
+}
+
+func (s *Server) listDataSources(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	datasources := make([]*datasource, 0)
+	for _, ds := range s.datasources {
+		if ds.orgId == orgId {
+			datasources = append(datasources, ds)
+		}
+	}
+	sort.Slice(datasources, func(i, j int) bool { return datasources[i].name < datasources[j].name })
+	result := make([]map[string]interface{}, 0, len(datasources))
+	for _, ds := range datasources {
+		result = append(result, ds.json())
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getDataSourceBy(by string) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		orgId, ok := s.orgID(w, r)
+		if !ok {
+			return
+		}
+		ds := s.findDataSource(orgId, by, p[by])
+		if ds == nil {
+			writeError(w, http.StatusNotFound, "Data source not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, ds.json())
+	}
+}
+
+// readDataSource reads a datasource sent by a client, splitting off its
+// secure JSON data.
+func readDataSource(w http.ResponseWriter, r *http.Request) (fields, secure map[string]interface{}, ok bool) {
+	if !readJSON(w, r, &fields) {
+		return nil, nil, false
+	}
+	secure, _ = fields["secureJsonData"].(map[string]interface{})
+	delete(fields, "secureJsonData")
+	delete(fields, "secureJsonFields")
+	return fields, secure, true
+}
+
+func (s *Server) postDataSource(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	fields, secure, ok := readDataSource(w, r)
+	if !ok {
+		return
+	}
+	name, _ := fields["name"].(string)
+	uid, _ := fields["uid"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Data source name is required")
+		return
+	}
+	if s.findDataSource(orgId, "name", name) != nil {
+		writeError(w, http.StatusConflict, "data source with the same name already exists")
+		return
+	}
+	if uid == "" {
+		uid = s.newUID()
+	} else if s.findDataSource(orgId, "uid", uid) != nil {
+		writeError(w, http.StatusConflict, "data source with the same uid already exists")
+		return
+	}
+	if secure == nil {
+		secure = map[string]interface{}{}
+	}
+	ds := &datasource{
+		id:      s.nextID("datasource"),
+		orgId:   orgId,
+		uid:     uid,
+		name:    name,
+		version: 1,
+		fields:  fields,
+		secure:  secure,
+	}
+	s.datasources[ds.id] = ds
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":         ds.id,
+		"name":       ds.name,
+		"message":    "Datasource added",
+		"datasource": ds.json(),
+	})
+}
+
+// putDataSource replaces a datasource. Secure JSON data keys that are not
+// sent keep their value. A version other than the current one is rejected
+// with a 409, like Grafana does.
+func (s *Server) putDataSource(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	ds := s.findDataSource(orgId, "id", p["id"])
+	if ds == nil {
+		writeError(w, http.StatusNotFound, "Data source not found")
+		return
+	}
+	fields, secure, ok := readDataSource(w, r)
+	if !ok {
+		return
+	}
+	if version, _ := fields["version"].(float64); version != 0 && int64(version) != ds.version {
+		writeError(w, http.StatusConflict, "Datasource has already been updated by someone else. Please reload and try again")
+		return
+	}
+	name, _ := fields["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Data source name is required")
+		return
+	}
+	if other := s.findDataSource(orgId, "name", name); other != nil && other != ds {
+		writeError(w, http.StatusConflict, "data source with the same name already exists")
+		return
+	}
+	if uid, _ := fields["uid"].(string); uid != "" && uid != ds.uid {
+		if s.findDataSource(orgId, "uid", uid) != nil {
+			writeError(w, http.StatusConflict, "data source with the same uid already exists")
+			return
+		}
+		ds.uid = uid
+	}
+	for k, v := range secure {
+		ds.secure[k] = v
+	}
+	ds.name = name
+	ds.fields = fields
+	ds.version++
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":         ds.id,
+		"name":       ds.name,
+		"message":    "Datasource updated",
+		"datasource": ds.json(),
+	})
+}
+
+func (s *Server) deleteDataSource(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	ds := s.findDataSource(orgId, "id", p["id"])
+	if ds == nil {
+		writeError(w, http.StatusNotFound, "Data source not found")
+		return
+	}
+	delete(s.datasources, ds.id)
+	writeError(w, http.StatusOK, "Data source deleted")
+}
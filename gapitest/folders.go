@@ -0,0 +1,395 @@
+package gapitest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+type folder struct {
+	id        int64
+	orgId     int64
+	uid       string
+	title     string
+	parentUid string
+	version   int
+	created   time.Time
+	updated   time.Time
+}
+
+func (f *folder) url() string {
+	return fmt.Sprintf("/dashboards/f/%s/%s", f.uid, slugify(f.title))
+}
+
+func (f *folder) json() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        f.id,
+		"uid":       f.uid,
+		"title":     f.title,
+		"url":       f.url(),
+		"hasAcl":    false,
+		"canSave":   true,
+		"canEdit":   true,
+		"canAdmin":  true,
+		"createdBy": "admin",
+		"created":   f.created,
+		"updatedBy": "admin",
+		"updated":   f.updated,
+		"version":   f.version,
+		"parentUid": f.parentUid,
+	}
+}
+
+func (s *Server) registerFolderRoutes() {
+	s.handle("GET", "/api/folders", s.listFolders)
+	s.handle("POST", "/api/folders", s.postFolder)
+	s.handle("GET", "/api/folders/id/:id", s.getFolderByID)
+	s.handle("GET", "/api/folders/:uid", s.getFolder)
+	s.handle("PUT", "/api/folders/:uid", s.putFolder)
+	s.handle("DELETE", "/api/folders/:uid", s.deleteFolder)
+	s.handle("POST", "/api/folders/:uid/move", s.moveFolder)
+	s.handle("GET", "/api/search", s.search)
+}
+
+func (s *Server) folderByUID(orgId int64, uid string) *folder {
+	for _, f := range s.folders {
+		if f.orgId == orgId && f.uid == uid {
+			return f
+		}
+	}
+	return nil
+}
+
+// folderUIDParam returns the folder of the uid path parameter in the org of
+// the request. It writes an error when either cannot be found.
+func (s *Server) folderUIDParam(w http.ResponseWriter, r *http.Request, p params) (*folder, bool) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return nil, false
+	}
+	f := s.folderByUID(orgId, p["uid"])
+	if f == nil {
+		writeError(w, http.StatusNotFound, "Folder not found")
+		return nil, false
+	}
+	return f, true
+}
+
+// uidTaken reports whether a folder or dashboard of the org uses uid, as
+// both share the same uids in Grafana.
+func (s *Server) uidTaken(orgId int64, uid string) bool {
+	return s.folderByUID(orgId, uid) != nil || s.dashboardByUID(orgId, uid) != nil
+}
+
+// folderTitleTaken reports whether another folder with the same parent has
+// the title.
+func (s *Server) folderTitleTaken(orgId int64, parentUid, title string, except *folder) bool {
+	for _, f := range s.folders {
+		if f != except && f.orgId == orgId && f.parentUid == parentUid && strings.EqualFold(f.title, title) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) childFolders(orgId int64, parentUid string) []*folder {
+	folders := make([]*folder, 0)
+	for _, f := range s.folders {
+		if f.orgId == orgId && f.parentUid == parentUid {
+			folders = append(folders, f)
+		}
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].title < folders[j].title })
+	return folders
+}
+
+func (s *Server) listFolders(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	parentUid := r.URL.Query().Get("parentUid")
+	if parentUid != "" && s.folderByUID(orgId, parentUid) == nil {
+		writeError(w, http.StatusNotFound, "Folder not found")
+		return
+	}
+	folders := s.childFolders(orgId, parentUid)
+	start, end := paginate(r, len(folders), 1000)
+	result := make([]map[string]interface{}, 0, end-start)
+	for _, f := range folders[start:end] {
+		result = append(result, map[string]interface{}{
+			"id":        f.id,
+			"uid":       f.uid,
+			"title":     f.title,
+			"parentUid": f.parentUid,
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) postFolder(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	body := struct {
+		Uid       string `json:"uid"`
+		Title     string `json:"title"`
+		ParentUid string `json:"parentUid"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Title == "" {
+		writeError(w, http.StatusBadRequest, "Folder title cannot be empty")
+		return
+	}
+	if body.ParentUid != "" && s.folderByUID(orgId, body.ParentUid) == nil {
+		writeError(w, http.StatusNotFound, "Parent folder not found")
+		return
+	}
+	if body.Uid == "" {
+		body.Uid = s.newUID()
+	} else if s.uidTaken(orgId, body.Uid) {
+		writeError(w, http.StatusConflict, "A folder or dashboard with the same uid already exists")
+		return
+	}
+	if s.folderTitleTaken(orgId, body.ParentUid, body.Title, nil) {
+		writeError(w, http.StatusConflict, "A folder with that name already exists")
+		return
+	}
+	now := s.now()
+	f := &folder{
+		id:        s.nextID("dashboard"),
+		orgId:     orgId,
+		uid:       body.Uid,
+		title:     body.Title,
+		parentUid: body.ParentUid,
+		version:   1,
+		created:   now,
+		updated:   now,
+	}
+	s.folders[f.id] = f
+	writeJSON(w, http.StatusOK, f.json())
+}
+
+func (s *Server) getFolderByID(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	f, ok := s.folders[p.int64("id")]
+	if !ok || f.orgId != orgId {
+		writeError(w, http.StatusNotFound, "Folder not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.folderWithParents(f))
+}
+
+func (s *Server) getFolder(w http.ResponseWriter, r *http.Request, p params) {
+	if f, ok := s.folderUIDParam(w, r, p); ok {
+		writeJSON(w, http.StatusOK, s.folderWithParents(f))
+	}
+}
+
+// folderWithParents returns a folder along with its ancestors, root first.
+func (s *Server) folderWithParents(f *folder) map[string]interface{} {
+	parents := make([]map[string]interface{}, 0)
+	for parent := s.folderByUID(f.orgId, f.parentUid); parent != nil; parent = s.folderByUID(parent.orgId, parent.parentUid) {
+		parents = append([]map[string]interface{}{parent.json()}, parents...)
+	}
+	result := f.json()
+	if len(parents) > 0 {
+		result["parents"] = parents
+	}
+	return result
+}
+
+func (s *Server) putFolder(w http.ResponseWriter, r *http.Request, p params) {
+	f, ok := s.folderUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	body := struct {
+		Uid       string `json:"uid"`
+		Title     string `json:"title"`
+		Version   int    `json:"version"`
+		Overwrite bool   `json:"overwrite"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if !body.Overwrite && body.Version != f.version {
+		writeVersionMismatch(w, "The folder has been changed by someone else")
+		return
+	}
+	if body.Title == "" {
+		writeError(w, http.StatusBadRequest, "Folder title cannot be empty")
+		return
+	}
+	if s.folderTitleTaken(f.orgId, f.parentUid, body.Title, f) {
+		writeError(w, http.StatusConflict, "A folder with that name already exists")
+		return
+	}
+	if body.Uid != "" && body.Uid != f.uid {
+		if s.uidTaken(f.orgId, body.Uid) {
+			writeError(w, http.StatusConflict, "A folder or dashboard with the same uid already exists")
+			return
+		}
+		for _, child := range s.folders {
+			if child.orgId == f.orgId && child.parentUid == f.uid {
+				child.parentUid = body.Uid
+			}
+		}
+		f.uid = body.Uid
+	}
+	f.title = body.Title
+	f.version++
+	f.updated = s.now()
+	writeJSON(w, http.StatusOK, f.json())
+}
+
+// deleteFolder deletes a folder along with its subfolders and the dashboards
+// in any of them.
+func (s *Server) deleteFolder(w http.ResponseWriter, r *http.Request, p params) {
+	f, ok := s.folderUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	s.removeFolder(f)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Folder %s deleted", f.title),
+		"id":      f.id,
+		"title":   f.title,
+	})
+}
+
+func (s *Server) removeFolder(f *folder) {
+	for _, child := range s.childFolders(f.orgId, f.uid) {
+		s.removeFolder(child)
+	}
+	for id, d := range s.dashboards {
+		if d.folderId == f.id {
+			delete(s.dashboards, id)
+		}
+	}
+	delete(s.folders, f.id)
+}
+
+func (s *Server) moveFolder(w http.ResponseWriter, r *http.Request, p params) {
+	f, ok := s.folderUIDParam(w, r, p)
+	if !ok {
+		return
+	}
+	body := struct {
+		ParentUid string `json:"parentUid"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.ParentUid != "" {
+		parent := s.folderByUID(f.orgId, body.ParentUid)
+		if parent == nil {
+			writeError(w, http.StatusNotFound, "Parent folder not found")
+			return
+		}
+		for ; parent != nil; parent = s.folderByUID(parent.orgId, parent.parentUid) {
+			if parent == f {
+				writeError(w, http.StatusBadRequest, "Cannot move a folder into itself or one of its subfolders")
+				return
+			}
+		}
+	}
+	if s.folderTitleTaken(f.orgId, body.ParentUid, f.title, f) {
+		writeError(w, http.StatusConflict, "A folder with that name already exists")
+		return
+	}
+	f.parentUid = body.ParentUid
+	f.version++
+	f.updated = s.now()
+	writeJSON(w, http.StatusOK, f.json())
+}
+
+// search implements the dashboard search, supporting the type, folderUIDs,
+// query, limit and page parameters. Results are sorted by title.
+func (s *Server) search(w http.ResponseWriter, r *http.Request, p params) {
+	orgId, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	kind := q.Get("type")
+	query := strings.ToLower(q.Get("query"))
+	var folderUIDs map[string]bool
+	if len(q["folderUIDs"]) > 0 {
+		folderUIDs = map[string]bool{}
+		for _, value := range q["folderUIDs"] {
+			for _, uid := range strings.Split(value, ",") {
+				folderUIDs[uid] = true
+			}
+		}
+	}
+
+	hits := make([]map[string]interface{}, 0)
+	if kind == "" || kind == "dash-folder" {
+		for _, f := range s.folders {
+			if f.orgId != orgId || !strings.Contains(strings.ToLower(f.title), query) {
+				continue
+			}
+			if folderUIDs != nil && !folderUIDs[f.parentUid] && !(f.parentUid == "" && folderUIDs["general"]) {
+				continue
+			}
+			hits = append(hits, map[string]interface{}{
+				"id":    f.id,
+				"uid":   f.uid,
+				"title": f.title,
+				"url":   f.url(),
+				"type":  "dash-folder",
+				"tags":  []string{},
+			})
+		}
+	}
+	if kind == "" || kind == "dash-db" {
+		for _, d := range s.dashboards {
+			if d.orgId != orgId || !strings.Contains(strings.ToLower(d.title()), query) {
+				continue
+			}
+			parent := s.folders[d.folderId]
+			folderUid := ""
+			if parent != nil {
+				folderUid = parent.uid
+			}
+			if folderUIDs != nil && !folderUIDs[folderUid] && !(folderUid == "" && folderUIDs["general"]) {
+				continue
+			}
+			hit := map[string]interface{}{
+				"id":        d.id,
+				"uid":       d.uid,
+				"title":     d.title(),
+				"uri":       "db/" + slugify(d.title()),
+				"url":       d.url(),
+				"type":      "dash-db",
+				"tags":      d.tags(),
+				"isStarred": false,
+			}
+			if parent != nil {
+				hit["folderId"] = parent.id
+				hit["folderUid"] = parent.uid
+				hit["folderTitle"] = parent.title
+				hit["folderUrl"] = parent.url()
+			}
+			hits = append(hits, hit)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		ti, tj := strings.ToLower(hits[i]["title"].(string)), strings.ToLower(hits[j]["title"].(string))
+		if ti != tj {
+			return ti < tj
+		}
+		return hits[i]["id"].(int64) < hits[j]["id"].(int64)
+	})
+	start, end := paginate(r, len(hits), 1000)
+	writeJSON(w, http.StatusOK, hits[start:end])
+}
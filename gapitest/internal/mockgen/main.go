@@ -0,0 +1,234 @@
+// Command mockgen generates the Mock of the gapitest package from the service
+// interfaces of the gapi package.
+//
+//	go run ./internal/mockgen -o mock_gen.go ../services.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const gapiImport = "github.com/vanugrah/go-grafana-api"
+
+type method struct {
+	name    string
+	params  []string
+	types   []string
+	results []string
+}
+
+type service struct {
+	name    string
+	methods []method
+}
+
+func main() {
+	out := flag.String("o", "mock_gen.go", "output file")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("usage: mockgen [-o file] services.go")
+	}
+	src := flag.Arg(0)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, src, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+
+	g := &generator{imports: imports, used: map[string]bool{}}
+	var services []service
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			svc := service{name: ts.Name.Name}
+			for _, field := range iface.Methods.List {
+				fn, ok := field.Type.(*ast.FuncType)
+				if !ok || len(field.Names) == 0 {
+					continue
+				}
+				svc.methods = append(svc.methods, g.method(field.Names[0].Name, fn))
+			}
+			if len(svc.methods) > 0 {
+				services = append(services, svc)
+			}
+		}
+	}
+
+	code, err := format.Source(g.render(filepath.Base(src), services))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	imports map[string]string
+	used    map[string]bool
+}
+
+func (g *generator) method(name string, fn *ast.FuncType) method {
+	m := method{name: name}
+	for _, field := range fn.Params.List {
+		typ := g.typeString(field.Type)
+		if len(field.Names) == 0 {
+			m.params = append(m.params, fmt.Sprintf("p%d", len(m.params)))
+			m.types = append(m.types, typ)
+		}
+		for _, n := range field.Names {
+			m.params = append(m.params, n.Name)
+			m.types = append(m.types, typ)
+		}
+	}
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			typ := g.typeString(field.Type)
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				m.results = append(m.results, typ)
+			}
+		}
+	}
+	return m
+}
+
+// typeString prints a type of the gapi package as seen from another package.
+func (g *generator) typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return "gapi." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + g.typeString(t.X)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			log.Fatalf("unsupported array type")
+		}
+		return "[]" + g.typeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + g.typeString(t.Key) + "]" + g.typeString(t.Value)
+	case *ast.SelectorExpr:
+		pkg := t.X.(*ast.Ident).Name
+		g.used[pkg] = true
+		return pkg + "." + t.Sel.Name
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	log.Fatalf("unsupported type %T", expr)
+	return ""
+}
+
+func (g *generator) render(src string, services []service) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen from %s. DO NOT EDIT.\n\n", src)
+	fmt.Fprintln(&buf, "package gapitest")
+	fmt.Fprintln(&buf, "\nimport (")
+	fmt.Fprintln(&buf, "\t\"sync\"")
+	var pkgs []string
+	for pkg := range g.used {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		fmt.Fprintf(&buf, "\t%q\n", g.imports[pkg])
+	}
+	fmt.Fprintf(&buf, "\n\tgapi %q\n)\n\n", gapiImport)
+
+	fmt.Fprintln(&buf, "// Mock implements gapi.Services. Every call is recorded and answered by the")
+	fmt.Fprintln(&buf, "// function field named after the method, or with zero values and a nil error")
+	fmt.Fprintln(&buf, "// when it is not set.")
+	fmt.Fprintln(&buf, "type Mock struct {")
+	for i, svc := range services {
+		if i > 0 {
+			fmt.Fprintln(&buf)
+		}
+		fmt.Fprintf(&buf, "\t// %s\n", svc.name)
+		for _, m := range svc.methods {
+			fmt.Fprintf(&buf, "\t%sFunc func(%s) %s\n", m.name, strings.Join(m.types, ", "), resultList(m.results))
+		}
+	}
+	fmt.Fprintln(&buf, "\n\tmu    sync.Mutex")
+	fmt.Fprintln(&buf, "\tcalls []Call")
+	fmt.Fprintln(&buf, "}")
+	fmt.Fprintln(&buf, "\nvar _ gapi.Services = (*Mock)(nil)")
+
+	for _, svc := range services {
+		for _, m := range svc.methods {
+			params := make([]string, len(m.params))
+			for i := range m.params {
+				params[i] = m.params[i] + " " + m.types[i]
+			}
+			args := strings.Join(m.params, ", ")
+			fmt.Fprintf(&buf, "\nfunc (m *Mock) %s(%s) %s {\n", m.name, strings.Join(params, ", "), resultList(m.results))
+			if args == "" {
+				fmt.Fprintf(&buf, "\tm.record(%q)\n", m.name)
+			} else {
+				fmt.Fprintf(&buf, "\tm.record(%q, %s)\n", m.name, args)
+			}
+			fmt.Fprintf(&buf, "\tif m.%sFunc != nil {\n", m.name)
+			if len(m.results) == 0 {
+				fmt.Fprintf(&buf, "\t\tm.%sFunc(%s)\n\t\treturn\n\t}\n}\n", m.name, args)
+				continue
+			}
+			fmt.Fprintf(&buf, "\t\treturn m.%sFunc(%s)\n\t}\n", m.name, args)
+			zeros := make([]string, len(m.results))
+			for i, typ := range m.results {
+				if typ == "error" {
+					zeros[i] = "nil"
+					continue
+				}
+				zeros[i] = fmt.Sprintf("r%d", i)
+				fmt.Fprintf(&buf, "\tvar r%d %s\n", i, typ)
+			}
+			fmt.Fprintf(&buf, "\treturn %s\n}\n", strings.Join(zeros, ", "))
+		}
+	}
+	return buf.Bytes()
+}
+
+func resultList(results []string) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return results[0]
+	}
+	return "(" + strings.Join(results, ", ") + ")"
+}
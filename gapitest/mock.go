@@ -0,0 +1,42 @@
+package gapitest
+
+//go:generate go run ./internal/mockgen -o mock_gen.go ../services.go
+
+// Call is a call made to a Mock.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls made to the mock, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to a method of the mock, in order.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]Call, 0)
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the calls made to the mock. Programmed responses are kept.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
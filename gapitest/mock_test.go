@@ -0,0 +1,53 @@
+package gapitest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+// ensureOps stands for code holding one of the service interfaces.
+func ensureOps(folders gapi.FolderService) (*gapi.Folder, error) {
+	folder, err := folders.GetFolderByUID("ops")
+	if err == nil {
+		return folder, nil
+	}
+	return folders.CreateFolder(&gapi.FolderCreateOpts{Uid: "ops", Title: "Ops"})
+}
+
+func TestMock(t *testing.T) {
+	mock := &Mock{
+		GetFolderByUIDFunc: func(uid string) (*gapi.Folder, error) {
+			return nil, errors.New("not found")
+		},
+		CreateFolderFunc: func(opts *gapi.FolderCreateOpts) (*gapi.Folder, error) {
+			return &gapi.Folder{Id: 7, Uid: opts.Uid, Title: opts.Title}, nil
+		},
+	}
+
+	folder, err := ensureOps(mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.Id != 7 {
+		t.Errorf("Unexpected folder %v", folder)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[0].Method != "GetFolderByUID" || !reflect.DeepEqual(calls[0].Args, []interface{}{"ops"}) {
+		t.Errorf("Unexpected calls %v", calls)
+	}
+	if len(mock.CallsTo("CreateFolder")) != 1 {
+		t.Errorf("CreateFolder should have been called once")
+	}
+
+	if err := mock.DeleteFolderByUID("ops"); err != nil {
+		t.Errorf("Methods without a programmed response should succeed, got %v", err)
+	}
+	mock.Reset()
+	if len(mock.Calls()) != 0 {
+		t.Errorf("Reset should forget calls")
+	}
+}
@@ -0,0 +1,392 @@
+package gapitest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+type org struct {
+	Id      int64           `json:"id"`
+	Name    string          `json:"name"`
+	Address gapi.OrgAddress `json:"address"`
+}
+
+func (s *Server) registerOrgRoutes() {
+	s.handle("GET", "/api/orgs", s.listOrgs)
+	s.handle("POST", "/api/orgs", s.postOrg)
+	s.handle("GET", "/api/orgs/name/:name", s.getOrgByName)
+	s.handle("GET", "/api/orgs/:id", s.getOrg)
+	s.handle("PUT", "/api/orgs/:id", s.putOrg)
+	s.handle("DELETE", "/api/orgs/:id", s.deleteOrg)
+	s.handle("GET", "/api/org", s.getCurrentOrg)
+	s.handle("PUT", "/api/org", s.putCurrentOrg)
+
+	s.handle("GET", "/api/orgs/:id/users/search", s.searchOrgUsers)
+	s.handle("GET", "/api/orgs/:id/users", s.listOrgUsers)
+	s.handle("POST", "/api/orgs/:id/users", s.postOrgUser)
+	s.handle("PATCH", "/api/orgs/:id/users/:userId", s.patchOrgUser)
+	s.handle("DELETE", "/api/orgs/:id/users/:userId", s.deleteOrgUser)
+	s.handle("GET", "/api/org/users/lookup", s.lookupCurrentOrgUsers)
+	s.handle("GET", "/api/org/users", s.listCurrentOrgUsers)
+	s.handle("POST", "/api/org/users", s.postCurrentOrgUser)
+	s.handle("PATCH", "/api/org/users/:userId", s.patchCurrentOrgUser)
+	s.handle("DELETE", "/api/org/users/:userId", s.deleteCurrentOrgUser)
+}
+
+func (s *Server) createOrg(name string) *org {
+	o := &org{Id: s.nextID("org"), Name: name}
+	s.orgs[o.Id] = o
+	s.members[o.Id] = map[int64]gapi.Role{}
+	return o
+}
+
+func (s *Server) orgByName(name string) *org {
+	for _, o := range s.orgs {
+		if strings.EqualFold(o.Name, name) {
+			return o
+		}
+	}
+	return nil
+}
+
+// orgParam returns the org of the id path parameter and writes a 404 when it
+// does not exist.
+func (s *Server) orgParam(w http.ResponseWriter, p params) (*org, bool) {
+	o, ok := s.orgs[p.int64("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Organization not found")
+	}
+	return o, ok
+}
+
+func (s *Server) listOrgs(w http.ResponseWriter, r *http.Request, p params) {
+	orgs := make([]*org, 0, len(s.orgs))
+	for _, o := range s.orgs {
+		orgs = append(orgs, o)
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Id < orgs[j].Id })
+	writeJSON(w, http.StatusOK, orgs)
+}
+
+func (s *Server) getOrgByName(w http.ResponseWriter, r *http.Request, p params) {
+	o := s.orgByName(p["name"])
+	if o == nil {
+		writeError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (s *Server) getOrg(w http.ResponseWriter, r *http.Request, p params) {
+	if o, ok := s.orgParam(w, p); ok {
+		writeJSON(w, http.StatusOK, o)
+	}
+}
+
+func (s *Server) postOrg(w http.ResponseWriter, r *http.Request, p params) {
+	body := struct {
+		Name string `json:"name"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "Organization name is required")
+		return
+	}
+	if s.orgByName(body.Name) != nil {
+		writeError(w, http.StatusConflict, "Organization name taken")
+		return
+	}
+	o := s.createOrg(body.Name)
+	s.members[o.Id][s.signedIn] = gapi.RoleAdmin
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Organization created",
+		"orgId":   o.Id,
+	})
+}
+
+func (s *Server) putOrg(w http.ResponseWriter, r *http.Request, p params) {
+	if o, ok := s.orgParam(w, p); ok {
+		s.renameOrg(w, r, o)
+	}
+}
+
+func (s *Server) putCurrentOrg(w http.ResponseWriter, r *http.Request, p params) {
+	if id, ok := s.orgID(w, r); ok {
+		s.renameOrg(w, r, s.orgs[id])
+	}
+}
+
+func (s *Server) renameOrg(w http.ResponseWriter, r *http.Request, o *org) {
+	body := struct {
+		Name string `json:"name"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if other := s.orgByName(body.Name); other != nil && other.Id != o.Id {
+		writeError(w, http.StatusConflict, "Organization name taken")
+		return
+	}
+	o.Name = body.Name
+	writeError(w, http.StatusOK, "Organization updated")
+}
+
+func (s *Server) getCurrentOrg(w http.ResponseWriter, r *http.Request, p params) {
+	if id, ok := s.orgID(w, r); ok {
+		writeJSON(w, http.StatusOK, s.orgs[id])
+	}
+}
+
+// deleteOrg deletes an organization along with everything scoped to it.
+func (s *Server) deleteOrg(w http.ResponseWriter, r *http.Request, p params) {
+	o, ok := s.orgParam(w, p)
+	if !ok {
+		return
+	}
+	delete(s.orgs, o.Id)
+	delete(s.members, o.Id)
+	for id, f := range s.folders {
+		if f.orgId == o.Id {
+			delete(s.folders, id)
+		}
+	}
+	for id, d := range s.dashboards {
+		if d.orgId == o.Id {
+			delete(s.dashboards, id)
+		}
+	}
+	for id, ds := range s.datasources {
+		if ds.orgId == o.Id {
+			delete(s.datasources, id)
+		}
+	}
+	for id, a := range s.alertNotifications {
+		if a.orgId == o.Id {
+			delete(s.alertNotifications, id)
+		}
+	}
+	for _, u := range s.users {
+		if u.OrgId == o.Id {
+			u.OrgId = s.anyOrgOf(u.Id)
+		}
+	}
+	writeError(w, http.StatusOK, "Organization deleted")
+}
+
+// anyOrgOf returns the lowest id of the orgs the user is a member of.
+func (s *Server) anyOrgOf(userId int64) int64 {
+	found := int64(0)
+	for orgId, members := range s.members {
+		if _, ok := members[userId]; ok && (found == 0 || orgId < found) {
+			found = orgId
+		}
+	}
+	return found
+}
+
+func (s *Server) orgUsers(orgId int64) []map[string]interface{} {
+	users := make([]map[string]interface{}, 0, len(s.members[orgId]))
+	for userId, role := range s.members[orgId] {
+		u := s.users[userId]
+		users = append(users, map[string]interface{}{
+			"orgId":      orgId,
+			"userId":     u.Id,
+			"email":      u.Email,
+			"name":       u.Name,
+			"avatarUrl":  u.avatarURL(),
+			"login":      u.Login,
+			"role":       role,
+			"lastSeenAt": u.created,
+			"isDisabled": u.IsDisabled,
+		})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i]["userId"].(int64) < users[j]["userId"].(int64) })
+	return users
+}
+
+func (s *Server) listOrgUsers(w http.ResponseWriter, r *http.Request, p params) {
+	if o, ok := s.orgParam(w, p); ok {
+		writeJSON(w, http.StatusOK, s.orgUsers(o.Id))
+	}
+}
+
+func (s *Server) listCurrentOrgUsers(w http.ResponseWriter, r *http.Request, p params) {
+	if id, ok := s.orgID(w, r); ok {
+		writeJSON(w, http.StatusOK, s.orgUsers(id))
+	}
+}
+
+func (s *Server) searchOrgUsers(w http.ResponseWriter, r *http.Request, p params) {
+	o, ok := s.orgParam(w, p)
+	if !ok {
+		return
+	}
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	matching := make([]map[string]interface{}, 0)
+	for _, ou := range s.orgUsers(o.Id) {
+		if query == "" || strings.Contains(strings.ToLower(ou["login"].(string)+" "+ou["email"].(string)+" "+ou["name"].(string)), query) {
+			matching = append(matching, ou)
+		}
+	}
+	start, end := paginate(r, len(matching), 1000)
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("perpage"))
+	if perPage <= 0 {
+		perPage = 1000
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"totalCount": len(matching),
+		"orgUsers":   matching[start:end],
+		"page":       page,
+		"perPage":    perPage,
+	})
+}
+
+func (s *Server) lookupCurrentOrgUsers(w http.ResponseWriter, r *http.Request, p params) {
+	id, ok := s.orgID(w, r)
+	if !ok {
+		return
+	}
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	result := make([]map[string]interface{}, 0)
+	for _, ou := range s.orgUsers(id) {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		if query == "" || strings.Contains(strings.ToLower(ou["login"].(string)+" "+ou["email"].(string)+" "+ou["name"].(string)), query) {
+			result = append(result, map[string]interface{}{
+				"userId":    ou["userId"],
+				"login":     ou["login"],
+				"avatarUrl": ou["avatarUrl"],
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) postOrgUser(w http.ResponseWriter, r *http.Request, p params) {
+	if o, ok := s.orgParam(w, p); ok {
+		s.addOrgUser(w, r, o.Id)
+	}
+}
+
+func (s *Server) postCurrentOrgUser(w http.ResponseWriter, r *http.Request, p params) {
+	if id, ok := s.orgID(w, r); ok {
+		s.addOrgUser(w, r, id)
+	}
+}
+
+func (s *Server) addOrgUser(w http.ResponseWriter, r *http.Request, orgId int64) {
+	body := struct {
+		LoginOrEmail string    `json:"loginOrEmail"`
+		Role         gapi.Role `json:"role"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Role.Validate() != nil {
+		writeError(w, http.StatusBadRequest, "Invalid role specified")
+		return
+	}
+	u := s.userByLoginOrEmail(body.LoginOrEmail)
+	if u == nil {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if _, ok := s.members[orgId][u.Id]; ok {
+		writeError(w, http.StatusConflict, "User is already member of this organization")
+		return
+	}
+	s.members[orgId][u.Id] = body.Role
+	if u.OrgId == 0 {
+		u.OrgId = orgId
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "User added to organization",
+		"userId":  u.Id,
+	})
+}
+
+func (s *Server) patchOrgUser(w http.ResponseWriter, r *http.Request, p params) {
+	if o, ok := s.orgParam(w, p); ok {
+		s.updateOrgUser(w, r, o.Id, p.int64("userId"))
+	}
+}
+
+func (s *Server) patchCurrentOrgUser(w http.ResponseWriter, r *http.Request, p params) {
+	if id, ok := s.orgID(w, r); ok {
+		s.updateOrgUser(w, r, id, p.int64("userId"))
+	}
+}
+
+func (s *Server) updateOrgUser(w http.ResponseWriter, r *http.Request, orgId, userId int64) {
+	body := struct {
+		Role gapi.Role `json:"role"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Role.Validate() != nil {
+		writeError(w, http.StatusBadRequest, "Invalid role specified")
+		return
+	}
+	role, ok := s.members[orgId][userId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if role == gapi.RoleAdmin && body.Role != gapi.RoleAdmin && s.isLastAdmin(orgId, userId) {
+		writeError(w, http.StatusBadRequest, "Cannot change role so that there is no organization admin left")
+		return
+	}
+	s.members[orgId][userId] = body.Role
+	writeError(w, http.StatusOK, "Organization user updated")
+}
+
+func (s *Server) deleteOrgUser(w http.ResponseWriter, r *http.Request, p params) {
+	if o, ok := s.orgParam(w, p); ok {
+		s.removeOrgUser(w, o.Id, p.int64("userId"))
+	}
+}
+
+func (s *Server) deleteCurrentOrgUser(w http.ResponseWriter, r *http.Request, p params) {
+	if id, ok := s.orgID(w, r); ok {
+		s.removeOrgUser(w, id, p.int64("userId"))
+	}
+}
+
+func (s *Server) removeOrgUser(w http.ResponseWriter, orgId, userId int64) {
+	role, ok := s.members[orgId][userId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if role == gapi.RoleAdmin && s.isLastAdmin(orgId, userId) {
+		writeError(w, http.StatusBadRequest, "Cannot remove last organization admin")
+		return
+	}
+	delete(s.members[orgId], userId)
+	if u := s.users[userId]; u.OrgId == orgId {
+		u.OrgId = s.anyOrgOf(userId)
+	}
+	writeError(w, http.StatusOK, "User removed from organization")
+}
+
+func (s *Server) isLastAdmin(orgId, userId int64) bool {
+	for id, role := range s.members[orgId] {
+		if id != userId && role == gapi.RoleAdmin {
+			return false
+		}
+	}
+	return true
+}
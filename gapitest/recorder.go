@@ -0,0 +1,263 @@
+package gapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+// RecorderMode selects whether a Recorder records or replays interactions.
+type RecorderMode int
+
+const (
+	// ModeRecord sends requests to Grafana and records them along with
+	// their responses.
+	ModeRecord RecorderMode = iota
+	// ModeReplay answers requests from a cassette without sending them.
+	ModeReplay
+)
+
+// Redacted replaces scrubbed header values and secret fields in cassettes.
+const Redacted = gapi.Redacted
+
+// scrubbedHeaders are the headers whose values are never recorded.
+var scrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Grafana-Device-Id"}
+
+// Cassette is the list of interactions recorded by a Recorder.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it got.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording the requests made through a
+// client to a cassette file, or replaying them from one. Set it as the
+// transport of the client:
+//
+//	recorder, err := gapitest.NewRecorder("testdata/folders.json", gapitest.ModeReplay)
+//	client.Transport = recorder
+//
+// Authentication headers, cookies and secret fields such as passwords and
+// secureJsonData values are scrubbed before interactions are recorded.
+//
+// In replay mode, a request is answered by the first interaction not used yet
+// with the same method, path, query and body. Requests matching none fail
+// with an error describing them.
+type Recorder struct {
+	// Transport sends the requests in record mode. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path string
+	mode RecorderMode
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder returns a recorder for the cassette at path. In replay mode the
+// cassette is loaded right away; in record mode it is written by Save.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("gapitest: invalid cassette %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// RoundTrip records or replays a request depending on the mode of the
+// recorder.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Header: scrubHeader(req.Header),
+		Body:   scrubBody(body),
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header),
+			Body:       scrubBody(body),
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		r.used[i] = true
+		resp := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        resp.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("gapitest: no interaction of %s matches %s %s?%s with body %q",
+		r.path, recorded.Method, recorded.Path, recorded.Query, recorded.Body)
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// Unused returns the interactions of the cassette no request matched in
+// replay mode, which usually means the code under test changed.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unused := make([]Interaction, 0)
+	for i, interaction := range r.cassette.Interactions {
+		if i < len(r.used) && !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// matches reports whether a request sent while replaying matches the
+// recorded one. JSON bodies are compared by value.
+func (rr RecordedRequest) matches(other RecordedRequest) bool {
+	if rr.Method != other.Method || rr.Path != other.Path {
+		return false
+	}
+	q1, err1 := url.ParseQuery(rr.Query)
+	q2, err2 := url.ParseQuery(other.Query)
+	if err1 != nil || err2 != nil || q1.Encode() != q2.Encode() {
+		return false
+	}
+	if rr.Body == other.Body {
+		return true
+	}
+	var b1, b2 interface{}
+	if json.Unmarshal([]byte(rr.Body), &b1) != nil || json.Unmarshal([]byte(other.Body), &b2) != nil {
+		return false
+	}
+	return reflect.DeepEqual(b1, b2)
+}
+
+// readBody reads the body of a request and puts it back so that it can still
+// be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func scrubHeader(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range scrubbedHeaders {
+		if _, ok := scrubbed[name]; ok {
+			scrubbed.Set(name, Redacted)
+		}
+	}
+	return scrubbed
+}
+
+// scrubBody redacts the secret fields of a JSON body with gapi.RedactJSON.
+// Other bodies are returned as is.
+func scrubBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	scrubbed, err := gapi.RedactJSON(body)
+	if err != nil {
+		return string(body)
+	}
+	return string(scrubbed)
+}
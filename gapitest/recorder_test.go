@@ -0,0 +1,104 @@
+package gapitest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gapitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cassette := filepath.Join(dir, "cassette.json")
+
+	server := NewServer()
+	recorder, err := NewRecorder(cassette, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := server.Client()
+	client.Transport = recorder
+
+	ds := &gapi.DataSource{
+		Name:           "prometheus",
+		Type:           "prometheus",
+		SecureJSONData: gapi.SecureJSONData{Password: "s3cr3t"},
+	}
+	id, err := client.NewDataSource(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DataSource(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"s3cr3t", "Basic "} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette should not contain %q:\n%s", secret, data)
+		}
+	}
+
+	recorder, err = NewRecorder(cassette, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = gapi.New("admin:other", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Transport = recorder
+
+	replayedId, err := client.NewDataSource(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := client.DataSource(replayedId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayedId != id || replayed.Name != "prometheus" || !replayed.SecureJSONFields["password"] {
+		t.Errorf("Unexpected replayed datasource %v", replayed)
+	}
+	if len(recorder.Unused()) != 0 {
+		t.Errorf("Every interaction should have been used, got %v", recorder.Unused())
+	}
+
+	_, err = client.DataSource(id)
+	if err == nil || !strings.Contains(err.Error(), "no interaction") {
+		t.Errorf("Unmatched requests should fail, got %v", err)
+	}
+}
+
+func TestRecordedRequestMatches(t *testing.T) {
+	recorded := RecordedRequest{Method: "POST", Path: "/api/folders", Query: "a=1&b=2", Body: `{"title":"Ops","uid":"ops"}`}
+
+	cases := []struct {
+		request RecordedRequest
+		matches bool
+	}{
+		{RecordedRequest{Method: "POST", Path: "/api/folders", Query: "b=2&a=1", Body: `{"uid":"ops","title":"Ops"}`}, true},
+		{RecordedRequest{Method: "PUT", Path: "/api/folders", Query: "a=1&b=2", Body: `{"title":"Ops","uid":"ops"}`}, false},
+		{RecordedRequest{Method: "POST", Path: "/api/folders", Query: "a=1", Body: `{"title":"Ops","uid":"ops"}`}, false},
+		{RecordedRequest{Method: "POST", Path: "/api/folders", Query: "a=1&b=2", Body: `{"title":"Dev","uid":"ops"}`}, false},
+	}
+	for _, c := range cases {
+		if recorded.matches(c.request) != c.matches {
+			t.Errorf("Matching %v against %v should be %v", c.request, recorded, c.matches)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Package gapitest provides an in-memory Grafana server for testing code
+// built on the gapi client.
+//
+// The server keeps orgs, users, org memberships, folders, dashboards,
+// datasources and alert notifications in memory and enforces the rules of a
+// real Grafana that automation usually trips over: unique names and uids,
+// 404s for missing resources, 412s for version mismatches and the scoping of
+// resources to the organization selected with the X-Grafana-Org-Id header or
+// the current org of the signed in user.
+//
+//	server := gapitest.NewServer()
+//	defer server.Close()
+//	client := server.Client()
+//
+// Every request is authenticated as the server admin "admin" (id 1), member of
+// the "Main Org." (id 1). Authentication headers are not checked.
+package gapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+// Server is an in-memory Grafana served by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	routes []route
+	now    func() time.Time
+
+	seq                map[string]int64
+	signedIn           int64
+	orgs               map[int64]*org
+	users              map[int64]*user
+	members            map[int64]map[int64]gapi.Role
+	folders            map[int64]*folder
+	dashboards         map[int64]*dashboard
+	datasources        map[int64]*datasource
+	alertNotifications map[int64]*alertNotification
+}
+
+// NewServer starts a server holding the "Main Org." and the "admin" user.
+// Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		now:                time.Now,
+		seq:                map[string]int64{},
+		orgs:               map[int64]*org{},
+		users:              map[int64]*user{},
+		members:            map[int64]map[int64]gapi.Role{},
+		folders:            map[int64]*folder{},
+		dashboards:         map[int64]*dashboard{},
+		datasources:        map[int64]*datasource{},
+		alertNotifications: map[int64]*alertNotification{},
+	}
+	s.handle("GET", "/api/health", s.getHealth)
+	s.handle("GET", "/api/frontend/settings", s.getFrontendSettings)
+	s.registerOrgRoutes()
+	s.registerUserRoutes()
+	s.registerFolderRoutes()
+	s.registerDashboardRoutes()
+	s.registerDataSourceRoutes()
+	s.registerAlertNotificationRoutes()
+
+	mainOrg := s.createOrg("Main Org.")
+	admin := &user{
+		Id:             s.nextID("user"),
+		Login:          "admin",
+		Email:          "admin@localhost",
+		IsGrafanaAdmin: true,
+		OrgId:          mainOrg.Id,
+		created:        s.now(),
+	}
+	s.users[admin.Id] = admin
+	s.signedIn = admin.Id
+	s.members[mainOrg.Id][admin.Id] = gapi.RoleAdmin
+
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Version is the Grafana version the server reports.
+const Version = "10.2.0"
+
+// Client returns a client for the server authenticated as the admin user.
+func (s *Server) Client() *gapi.Client {
+	client, err := gapi.New("admin:admin", s.URL)
+	if err != nil {
+		panic(fmt.Sprintf("gapitest: %v", err))
+	}
+	return client
+}
+
+// ServeHTTP serves the Grafana HTTP API. Requests are handled one at a time.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := splitPath(r.URL.Path)
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+		if p, ok := rt.match(segments); ok {
+			rt.handler(w, r, p)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found")
+}
+
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"commit":   "gapitest",
+		"database": "ok",
+		"version":  Version,
+	})
+}
+
+func (s *Server) getFrontendSettings(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"buildInfo": map[string]interface{}{
+			"version": Version,
+			"commit":  "gapitest",
+			"edition": "Open Source",
+			"env":     "production",
+		},
+	})
+}
+
+type params map[string]string
+
+// int64 returns the named path parameter as an integer.
+func (p params) int64(name string) int64 {
+	id, _ := strconv.ParseInt(p[name], 10, 64)
+	return id
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, p params)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// handle registers a handler for a path pattern in which segments starting
+// with a colon are parameters, e.g. "/api/orgs/:id". Routes are tried in the
+// order they are registered.
+func (s *Server) handle(method, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{method, splitPath(pattern), handler})
+}
+
+func (rt route) match(segments []string) (params, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	p := params{}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, ":") {
+			p[seg[1:]] = segments[i]
+		} else if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+// nextID returns the next id of a kind of resource. Ids start at 1.
+func (s *Server) nextID(kind string) int64 {
+	s.seq[kind]++
+	return s.seq[kind]
+}
+
+// newUID generates a uid unique across all kinds of resources.
+func (s *Server) newUID() string {
+	return fmt.Sprintf("gen%06d", s.nextID("uid"))
+}
+
+// orgID returns the organization a request acts on and writes an error when
+// the signed in user cannot act on it.
+func (s *Server) orgID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id := s.users[s.signedIn].OrgId
+	if header := r.Header.Get("X-Grafana-Org-Id"); header != "" {
+		var err error
+		id, err = strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid X-Grafana-Org-Id header")
+			return 0, false
+		}
+	}
+	if _, ok := s.members[id][s.signedIn]; !ok {
+		writeError(w, http.StatusUnauthorized, "Access denied to organization")
+		return 0, false
+	}
+	return id, true
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"message": message})
+}
+
+func writeVersionMismatch(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusPreconditionFailed, map[string]string{
+		"message": message,
+		"status":  "version-mismatch",
+	})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "bad request data")
+		return false
+	}
+	return true
+}
+
+// paginate returns the part of n items on the page selected by the limit (or
+// perpage) and page query parameters.
+func paginate(r *http.Request, n, defaultLimit int) (start, end int) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		limit, _ = strconv.Atoi(query.Get("perpage"))
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	start = (page - 1) * limit
+	if start > n {
+		start = n
+	}
+	end = start + limit
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(title string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
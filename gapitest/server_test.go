@@ -0,0 +1,238 @@
+package gapitest
+
+import (
+	"testing"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+func statusCode(err error) int {
+	if gerr, ok := err.(*gapi.GrafanaError); ok {
+		return gerr.StatusCode
+	}
+	return 0
+}
+
+func TestOrgsAndMembers(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	orgId, err := client.NewOrg("Ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.NewOrg("Ops"); statusCode(err) != 409 {
+		t.Errorf("Duplicate org names should be rejected with a 409, got %v", err)
+	}
+
+	userId, err := client.CreateUser(gapi.User{Login: "alice", Email: "alice@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateUser(gapi.User{Login: "alice", Password: "secret"}); statusCode(err) != 412 {
+		t.Errorf("Duplicate logins should be rejected with a 412, got %v", err)
+	}
+
+	if err := client.AddOrgUser(orgId, "alice@example.com", gapi.RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddOrgUser(orgId, "alice", gapi.RoleViewer); statusCode(err) != 409 {
+		t.Errorf("Adding a member twice should be rejected with a 409, got %v", err)
+	}
+	if err := client.AddOrgUser(orgId, "bob", gapi.RoleViewer); statusCode(err) != 404 {
+		t.Errorf("Adding an unknown user should fail with a 404, got %v", err)
+	}
+
+	users, err := client.OrgUsers(orgId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[1].UserId != userId || users[1].Role != gapi.RoleEditor {
+		t.Errorf("Unexpected org users %v", users)
+	}
+
+	orgs, err := client.UserOrgs(userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 2 || orgs[0].Role != gapi.RoleViewer || orgs[1].Name != "Ops" {
+		t.Errorf("Unexpected user orgs %v", orgs)
+	}
+
+	if err := client.UpdateOrgUser(orgId, 1, gapi.RoleViewer); statusCode(err) != 400 {
+		t.Errorf("Demoting the last admin should be rejected, got %v", err)
+	}
+
+	if err := client.DeleteOrg(orgId); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Org(orgId); statusCode(err) != 404 {
+		t.Errorf("Deleted org should not be found, got %v", err)
+	}
+}
+
+func TestFoldersAndDashboards(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	parent, err := client.CreateFolder(&gapi.FolderCreateOpts{Title: "Ops", Uid: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := client.CreateFolder(&gapi.FolderCreateOpts{Title: "Databases", ParentUid: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateFolder(&gapi.FolderCreateOpts{Title: "Other", Uid: "ops"}); statusCode(err) != 409 {
+		t.Errorf("Duplicate folder uids should be rejected with a 409, got %v", err)
+	}
+	if _, err := client.MoveFolder("ops", child.Uid); statusCode(err) != 400 {
+		t.Errorf("Moving a folder under its own subfolder should be rejected, got %v", err)
+	}
+
+	if _, err := client.UpdateFolder(&gapi.FolderUpdateOpts{Uid: "ops", Title: "Operations", Version: parent.Version + 1}); statusCode(err) != 412 {
+		t.Errorf("Folder updates against another version should fail with a 412, got %v", err)
+	}
+	updated, err := client.UpdateFolder(&gapi.FolderUpdateOpts{Uid: "ops", Title: "Operations", Version: parent.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Version != parent.Version+1 {
+		t.Errorf("Folder version should be bumped, got %d", updated.Version)
+	}
+
+	ancestors, err := client.GetFolderAncestors(child.Uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ancestors) != 1 || ancestors[0].Uid != "ops" {
+		t.Errorf("Unexpected ancestors %v", ancestors)
+	}
+
+	saved, err := client.SaveDashboard(&gapi.DashboardSaveOpts{
+		Model:    map[string]interface{}{"title": "Postgres"},
+		FolderID: child.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Uid == "" || saved.Version != 1 {
+		t.Errorf("Unexpected save response %v", saved)
+	}
+
+	stale := map[string]interface{}{"uid": saved.Uid, "title": "Postgres", "version": 0}
+	if _, err := client.SaveDashboard(&gapi.DashboardSaveOpts{Model: stale, FolderID: child.Id}); statusCode(err) != 412 {
+		t.Errorf("Stale dashboard saves should fail with a 412, got %v", err)
+	}
+	if _, err := client.SaveDashboard(&gapi.DashboardSaveOpts{Model: map[string]interface{}{"title": "Postgres"}, FolderID: child.Id}); statusCode(err) != 412 {
+		t.Errorf("Dashboards with the title of another one in the folder should fail with a 412, got %v", err)
+	}
+	current := map[string]interface{}{"uid": saved.Uid, "title": "Postgres", "version": 1}
+	if saved, err = client.SaveDashboard(&gapi.DashboardSaveOpts{Model: current, FolderID: child.Id}); err != nil || saved.Version != 2 {
+		t.Errorf("Dashboard update failed: %v %v", saved, err)
+	}
+
+	tree, err := client.GetFolderTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 2 || tree[0].Dashboards != 0 || tree[1].TotalDashboards() != 1 || tree[1].Children[0].Dashboards != 1 {
+		t.Errorf("Unexpected folder tree %v", tree)
+	}
+
+	if err := client.DeleteFolderByUID("ops"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetDashboardByUID(saved.Uid); statusCode(err) != 404 {
+		t.Errorf("Dashboards should be deleted along with their folder, got %v", err)
+	}
+}
+
+func TestDataSources(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	ds := &gapi.DataSource{
+		Name:           "prometheus",
+		Type:           "prometheus",
+		URL:            "http://prometheus:9090",
+		Access:         "proxy",
+		SecureJSONData: gapi.SecureJSONData{Password: "secret"},
+	}
+	id, err := client.NewDataSource(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.NewDataSource(ds); statusCode(err) != 409 {
+		t.Errorf("Duplicate datasource names should be rejected with a 409, got %v", err)
+	}
+
+	fields, err := client.RotateDataSourceSecrets(id, gapi.DataSourceSecrets{SecureJSONData: gapi.SecureJSONData{BasicAuthPassword: "other"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fields["password"] || !fields["basicAuthPassword"] {
+		t.Errorf("Secure fields should be kept across updates, got %v", fields)
+	}
+
+	byName, err := client.DataSourceByName("prometheus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byName.Id != id || byName.SecureJSONData.Password != "" {
+		t.Errorf("Unexpected datasource %v", byName)
+	}
+}
+
+func TestOrgScoping(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	orgId, err := client.NewOrg("Ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoped := client.WithOrgID(orgId)
+	if _, err := scoped.NewDataSource(&gapi.DataSource{Name: "loki", Type: "loki"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.NewDataSource(&gapi.DataSource{Name: "loki", Type: "loki"}); err != nil {
+		t.Errorf("Datasource names should be unique per org only, got %v", err)
+	}
+	if _, err := client.DataSourceByName("loki"); err != nil {
+		t.Error(err)
+	}
+
+	n, err := scoped.NewAlertNotification(&gapi.AlertNotification{Name: "pager", Type: "pagerduty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AlertNotification(n); err == nil {
+		t.Error("Alert notifications of another org should not be found")
+	}
+
+	if _, err := client.WithOrgID(42).DataSources(); statusCode(err) != 401 {
+		t.Errorf("Requests to orgs the user is not a member of should be denied, got %v", err)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Version != Version || health.BuildInfo.Version != Version {
+		t.Errorf("Unexpected health %v", health)
+	}
+	if ok, err := client.Supports(gapi.CapabilityNestedFolders); err != nil || !ok {
+		t.Errorf("The server should support nested folders: %v %v", ok, err)
+	}
+}
@@ -0,0 +1,297 @@
+package gapitest
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+type user struct {
+	Id             int64
+	Login          string
+	Email          string
+	Name           string
+	Theme          string
+	Password       string
+	IsGrafanaAdmin bool
+	IsDisabled     bool
+	// OrgId is the current organization of the user.
+	OrgId   int64
+	created time.Time
+}
+
+func (u *user) avatarURL() string {
+	return fmt.Sprintf("/avatar/%x", md5.Sum([]byte(strings.ToLower(u.Email))))
+}
+
+// listed is the user as found in user lists and searches.
+func (u *user) listed() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         u.Id,
+		"email":      u.Email,
+		"name":       u.Name,
+		"login":      u.Login,
+		"avatarUrl":  u.avatarURL(),
+		"isAdmin":    u.IsGrafanaAdmin,
+		"isDisabled": u.IsDisabled,
+		"lastSeenAt": u.created,
+		"authLabels": []string{},
+	}
+}
+
+// profile is the user as returned on its own.
+func (u *user) profile() map[string]interface{} {
+	return map[string]interface{}{
+		"id":             u.Id,
+		"email":          u.Email,
+		"name":           u.Name,
+		"login":          u.Login,
+		"theme":          u.Theme,
+		"orgId":          u.OrgId,
+		"isGrafanaAdmin": u.IsGrafanaAdmin,
+		"isDisabled":     u.IsDisabled,
+		"createdAt":      u.created,
+		"updatedAt":      u.created,
+	}
+}
+
+func (s *Server) registerUserRoutes() {
+	s.handle("POST", "/api/admin/users", s.postUser)
+	s.handle("DELETE", "/api/admin/users/:id", s.deleteUser)
+	s.handle("GET", "/api/users", s.listUsers)
+	s.handle("GET", "/api/users/lookup", s.lookupUser)
+	s.handle("GET", "/api/users/search", s.searchUsers)
+	s.handle("GET", "/api/users/:id", s.getUser)
+	s.handle("PUT", "/api/users/:id", s.putUser)
+	s.handle("GET", "/api/users/:id/orgs", s.getUserOrgs)
+	s.handle("GET", "/api/users/:id/teams", s.getUserTeams)
+	s.handle("GET", "/api/user", s.getSignedInUser)
+	s.handle("GET", "/api/user/orgs", s.getSignedInUserOrgs)
+	s.handle("GET", "/api/user/teams", s.getUserTeams)
+	s.handle("POST", "/api/user/using/:id", s.switchOrg)
+}
+
+func (s *Server) sortedUsers() []*user {
+	users := make([]*user, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users
+}
+
+func (s *Server) userByLoginOrEmail(loginOrEmail string) *user {
+	for _, u := range s.users {
+		if strings.EqualFold(u.Login, loginOrEmail) || strings.EqualFold(u.Email, loginOrEmail) {
+			return u
+		}
+	}
+	return nil
+}
+
+// userParam returns the user of the id path parameter and writes a 404 when
+// it does not exist.
+func (s *Server) userParam(w http.ResponseWriter, p params) (*user, bool) {
+	u, ok := s.users[p.int64("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "User not found")
+	}
+	return u, ok
+}
+
+func (s *Server) postUser(w http.ResponseWriter, r *http.Request, p params) {
+	body := struct {
+		Email    string `json:"email"`
+		Name     string `json:"name"`
+		Login    string `json:"login"`
+		Password string `json:"password"`
+		OrgId    int64  `json:"orgId"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if body.Login == "" {
+		body.Login = body.Email
+	}
+	if body.Login == "" {
+		writeError(w, http.StatusBadRequest, "Login or email is required")
+		return
+	}
+	if s.userByLoginOrEmail(body.Login) != nil || (body.Email != "" && s.userByLoginOrEmail(body.Email) != nil) {
+		writeError(w, http.StatusPreconditionFailed, "User with email '"+body.Email+"' or username '"+body.Login+"' already exists")
+		return
+	}
+	orgId := body.OrgId
+	if orgId == 0 {
+		orgId = 1
+	}
+	if _, ok := s.orgs[orgId]; !ok {
+		writeError(w, http.StatusBadRequest, "Organization not found")
+		return
+	}
+	u := &user{
+		Id:       s.nextID("user"),
+		Login:    body.Login,
+		Email:    body.Email,
+		Name:     body.Name,
+		Password: body.Password,
+		OrgId:    orgId,
+		created:  s.now(),
+	}
+	s.users[u.Id] = u
+	s.members[orgId][u.Id] = gapi.RoleViewer
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      u.Id,
+		"message": "User created",
+	})
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, p params) {
+	u, ok := s.userParam(w, p)
+	if !ok {
+		return
+	}
+	if u.Id == s.signedIn {
+		writeError(w, http.StatusBadRequest, "Cannot delete the signed in user")
+		return
+	}
+	delete(s.users, u.Id)
+	for _, members := range s.members {
+		delete(members, u.Id)
+	}
+	writeError(w, http.StatusOK, "User deleted")
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, p params) {
+	users := make([]map[string]interface{}, 0, len(s.users))
+	for _, u := range s.sortedUsers() {
+		users = append(users, u.listed())
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) lookupUser(w http.ResponseWriter, r *http.Request, p params) {
+	u := s.userByLoginOrEmail(r.URL.Query().Get("loginOrEmail"))
+	if u == nil {
+		writeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, u.profile())
+}
+
+func (s *Server) searchUsers(w http.ResponseWriter, r *http.Request, p params) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	matching := make([]map[string]interface{}, 0)
+	for _, u := range s.sortedUsers() {
+		if query == "" || strings.Contains(strings.ToLower(u.Login+" "+u.Email+" "+u.Name), query) {
+			matching = append(matching, u.listed())
+		}
+	}
+	start, end := paginate(r, len(matching), 1000)
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("perpage"))
+	if perPage <= 0 {
+		perPage = 1000
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"totalCount": len(matching),
+		"users":      matching[start:end],
+		"page":       page,
+		"perPage":    perPage,
+	})
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, p params) {
+	if u, ok := s.userParam(w, p); ok {
+		writeJSON(w, http.StatusOK, u.profile())
+	}
+}
+
+func (s *Server) putUser(w http.ResponseWriter, r *http.Request, p params) {
+	u, ok := s.userParam(w, p)
+	if !ok {
+		return
+	}
+	body := struct {
+		Email string `json:"email"`
+		Name  string `json:"name"`
+		Login string `json:"login"`
+		Theme string `json:"theme"`
+	}{}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	for _, other := range []string{body.Login, body.Email} {
+		if found := s.userByLoginOrEmail(other); other != "" && found != nil && found.Id != u.Id {
+			writeError(w, http.StatusConflict, "User with that email or login already exists")
+			return
+		}
+	}
+	if body.Login != "" {
+		u.Login = body.Login
+	}
+	if body.Email != "" {
+		u.Email = body.Email
+	}
+	u.Name = body.Name
+	u.Theme = body.Theme
+	writeError(w, http.StatusOK, "User updated")
+}
+
+func (s *Server) userOrgs(userId int64) []map[string]interface{} {
+	orgs := make([]map[string]interface{}, 0)
+	for orgId, members := range s.members {
+		if role, ok := members[userId]; ok {
+			orgs = append(orgs, map[string]interface{}{
+				"orgId": orgId,
+				"name":  s.orgs[orgId].Name,
+				"role":  role,
+			})
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i]["orgId"].(int64) < orgs[j]["orgId"].(int64) })
+	return orgs
+}
+
+func (s *Server) getUserOrgs(w http.ResponseWriter, r *http.Request, p params) {
+	if u, ok := s.userParam(w, p); ok {
+		writeJSON(w, http.StatusOK, s.userOrgs(u.Id))
+	}
+}
+
+// getUserTeams reports no teams, which the server does not keep.
+func (s *Server) getUserTeams(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := p["id"]; ok {
+		if _, ok := s.userParam(w, p); !ok {
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, []interface{}{})
+}
+
+func (s *Server) getSignedInUser(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, s.users[s.signedIn].profile())
+}
+
+func (s *Server) getSignedInUserOrgs(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, s.userOrgs(s.signedIn))
+}
+
+func (s *Server) switchOrg(w http.ResponseWriter, r *http.Request, p params) {
+	orgId := p.int64("id")
+	if _, ok := s.members[orgId][s.signedIn]; !ok {
+		writeError(w, http.StatusUnauthorized, "Not a valid organization")
+		return
+	}
+	s.users[s.signedIn].OrgId = orgId
+	writeError(w, http.StatusOK, "Active organization changed")
+}
@@ -0,0 +1,71 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Health is the state of the server reported by /api/health, along with its
+// build info.
+type Health struct {
+	Commit   string `json:"commit"`
+	Database string `json:"database"`
+	// Version is empty when the server hides it from unauthenticated users.
+	Version   string    `json:"version"`
+	BuildInfo BuildInfo `json:"buildInfo"`
+}
+
+// BuildInfo is the build of the server as reported by its frontend settings.
+type BuildInfo struct {
+	Version       string `json:"version"`
+	Commit        string `json:"commit"`
+	Edition       string `json:"edition"`
+	Env           string `json:"env"`
+	LatestVersion string `json:"latestVersion"`
+	HasUpdate     bool   `json:"hasUpdate"`
+	HideVersion   bool   `json:"hideVersion"`
+}
+
+// Health returns the health of the server. The database is healthy when
+// Database is "ok". A server whose database is failing answers with a 503,
+// which is returned as an unhealthy Health rather than an error.
+func (c *Client) Health() (*Health, error) {
+	req, err := c.newRequest("Health", "GET", "/api/health", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to perform HTTP request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 && resp.StatusCode != 503 {
+		var gmsg GrafanaErrorMessage
+		json.NewDecoder(resp.Body).Decode(&gmsg)
+		return nil, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	health := &Health{}
+	if err := json.NewDecoder(resp.Body).Decode(health); err != nil {
+		return nil, err
+	}
+
+	buildInfo, err := c.BuildInfo()
+	if err != nil {
+		return nil, err
+	}
+	health.BuildInfo = *buildInfo
+	return health, nil
+}
+
+// BuildInfo returns the build of the server from /api/frontend/settings.
+func (c *Client) BuildInfo() (*BuildInfo, error) {
+	settings := struct {
+		BuildInfo BuildInfo `json:"buildInfo"`
+	}{}
+	if err := c.getJSON("BuildInfo", "/api/frontend/settings", &settings); err != nil {
+		return nil, err
+	}
+	return &settings.BuildInfo, nil
+}
@@ -0,0 +1,100 @@
+package instrument
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	gapi "github.com/vanugrah/go-grafana-api"
+	"github.com/vanugrah/go-grafana-api/gapitest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMetrics(t *testing.T) {
+	server := gapitest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	metrics := NewMetrics()
+	client.Use(metrics.Middleware())
+
+	if _, err := client.NewDataSource(&gapi.DataSource{Name: "prom", Type: "prometheus"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DataSourceByUID("missing"); err == nil {
+		t.Fatal("Expected an error for a missing data source")
+	}
+	req, err := http.NewRequest("GET", server.URL+"/api/datasources/uid/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP grafana_client_request_errors_total Requests to Grafana that failed, by operation and class of error.
+# TYPE grafana_client_request_errors_total counter
+grafana_client_request_errors_total{class="not_found",operation="DataSourceByUID"} 1
+grafana_client_request_errors_total{class="not_found",operation="unknown"} 1
+# HELP grafana_client_requests_total Requests sent to Grafana, by operation and status code.
+# TYPE grafana_client_requests_total counter
+grafana_client_requests_total{code="200",operation="NewDataSource"} 1
+grafana_client_requests_total{code="404",operation="DataSourceByUID"} 1
+grafana_client_requests_total{code="404",operation="unknown"} 1
+`
+	if err := testutil.CollectAndCompare(metrics, strings.NewReader(expected),
+		"grafana_client_requests_total", "grafana_client_request_errors_total"); err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(metrics, "grafana_client_request_duration_seconds"); n != 3 {
+		t.Errorf("Expected latencies for 3 operations, got %d", n)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	server := gapitest.NewServer()
+	defer server.Close()
+	client := server.Client().WithOrgID(1)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	var traceparent string
+	client.Use(Tracing(provider, propagation.TraceContext{}), func(next gapi.RoundTripFunc) gapi.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			traceparent = req.Header.Get("traceparent")
+			return next(req)
+		}
+	})
+
+	if _, err := client.DataSourceByUID("missing"); err == nil {
+		t.Fatal("Expected an error for a missing data source")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "DataSourceByUID" || span.SpanKind != trace.SpanKindClient || span.Status.Code != codes.Error {
+		t.Errorf("Unexpected %v span %s with status %v", span.SpanKind, span.Name, span.Status)
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["http.route"].AsString() != "/api/datasources/uid/:uid" ||
+		attrs["http.method"].AsString() != "GET" ||
+		attrs["http.status_code"].AsInt64() != 404 ||
+		attrs["grafana.org_id"].AsInt64() != 1 {
+		t.Errorf("Unexpected attributes %v", span.Attributes)
+	}
+	if !strings.Contains(traceparent, span.SpanContext.TraceID().String()) {
+		t.Errorf("Expected the trace context to be injected, got traceparent %q", traceparent)
+	}
+}
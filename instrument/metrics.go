@@ -0,0 +1,133 @@
+// Package instrument records the requests of a gapi client as Prometheus
+// metrics and OpenTelemetry spans.
+//
+//	metrics := instrument.NewMetrics()
+//	prometheus.MustRegister(metrics)
+//	client.Use(metrics.Middleware(), instrument.Tracing(nil))
+package instrument
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gapi "github.com/vanugrah/go-grafana-api"
+)
+
+// ErrorClass groups failed requests.
+type ErrorClass string
+
+const (
+	// ErrorTransport is a request that got no response.
+	ErrorTransport          ErrorClass = "transport"
+	ErrorUnauthorized       ErrorClass = "unauthorized"
+	ErrorNotFound           ErrorClass = "not_found"
+	ErrorConflict           ErrorClass = "conflict"
+	ErrorPreconditionFailed ErrorClass = "precondition_failed"
+	ErrorClient             ErrorClass = "client_error"
+	ErrorServer             ErrorClass = "server_error"
+)
+
+// Classify returns the class of a failed request, or an empty class when the
+// request succeeded.
+func Classify(resp *http.Response, err error) ErrorClass {
+	if err != nil || resp == nil {
+		return ErrorTransport
+	}
+	switch code := resp.StatusCode; {
+	case code == 401 || code == 403:
+		return ErrorUnauthorized
+	case code == 404:
+		return ErrorNotFound
+	case code == 409:
+		return ErrorConflict
+	case code == 412:
+		return ErrorPreconditionFailed
+	case code >= 500:
+		return ErrorServer
+	case code >= 400:
+		return ErrorClient
+	}
+	return ""
+}
+
+// unknownOperation labels the requests not made by a client method, sent by
+// calling Do directly. Their path may hold ids, which would make a label per
+// request.
+const unknownOperation = "unknown"
+
+func operationName(op gapi.Operation) string {
+	if op.Name != "" {
+		return op.Name
+	}
+	return unknownOperation
+}
+
+// Metrics counts the requests of clients and their errors, and measures their
+// latency, per operation. It is a prometheus.Collector.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics returns metrics to register with a Prometheus registry and add to
+// clients with Middleware.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grafana_client_requests_total",
+			Help: "Requests sent to Grafana, by operation and status code.",
+		}, []string{"operation", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grafana_client_request_errors_total",
+			Help: "Requests to Grafana that failed, by operation and class of error.",
+		}, []string{"operation", "class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grafana_client_request_duration_seconds",
+			Help:    "Time taken by requests to Grafana to get a response, by operation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// Middleware records the requests of the client it is added to. The code of
+// requests that got no response is "error". Latencies do not include the time
+// requests wait for the rate limiter of the client.
+func (m *Metrics) Middleware() gapi.Middleware {
+	return func(next gapi.RoundTripFunc) gapi.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			op, _ := gapi.RequestOperation(req)
+			name := operationName(op)
+
+			start := time.Now()
+			resp, err := next(req)
+			m.duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+			code := "error"
+			if err == nil {
+				code = strconv.Itoa(resp.StatusCode)
+			}
+			m.requests.WithLabelValues(name, code).Inc()
+			if class := Classify(resp, err); class != "" {
+				m.errors.WithLabelValues(name, string(class)).Inc()
+			}
+			return resp, err
+		}
+	}
+}
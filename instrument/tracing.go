@@ -0,0 +1,61 @@
+package instrument
+
+import (
+	"net/http"
+
+	gapi "github.com/vanugrah/go-grafana-api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/vanugrah/go-grafana-api/instrument"
+
+// Tracing creates a client span for each request of the client it is added
+// to, as a child of the span in the context of the request, and injects the
+// trace context into the request headers. A nil provider uses the global one,
+// and the global propagator is used unless others are given.
+func Tracing(provider trace.TracerProvider, propagators ...propagation.TextMapPropagator) gapi.Middleware {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	tracer := provider.Tracer(tracerName)
+	return func(next gapi.RoundTripFunc) gapi.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			op, _ := gapi.RequestOperation(req)
+			attrs := []attribute.KeyValue{
+				attribute.String("http.method", req.Method),
+				attribute.String("http.route", op.Route),
+				attribute.String("grafana.operation", operationName(op)),
+			}
+			if op.OrgID != 0 {
+				attrs = append(attrs, attribute.Int64("grafana.org_id", op.OrgID))
+			}
+			ctx, span := tracer.Start(req.Context(), operationName(op),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...))
+			defer span.End()
+
+			propagator := otel.GetTextMapPropagator()
+			if len(propagators) > 0 {
+				propagator = propagation.NewCompositeTextMapPropagator(propagators...)
+			}
+			req = req.Clone(ctx)
+			propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			if class := Classify(resp, nil); class != "" {
+				span.SetStatus(codes.Error, string(class))
+			}
+			return resp, err
+		}
+	}
+}
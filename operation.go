@@ -0,0 +1,180 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Operation describes the request a client method makes.
+type Operation struct {
+	// Name is the Client method making the request, e.g. "SaveDashboard".
+	// It is empty for requests not built by the client, sent by calling Do
+	// directly.
+	Name string
+	// Method is the HTTP method of the request.
+	Method string
+	// Route is the route template of the endpoint, e.g.
+	// "/api/dashboards/uid/:uid". Paths the client has no template for are
+	// their own route.
+	Route string
+	// Params holds the values of the parameters of Route, e.g. "uid".
+	Params map[string]string
+	// OrgID is the organization set with WithOrgID, if any.
+	OrgID int64
+}
+
+// Class returns whether the operation reads or writes.
+func (op Operation) Class() EndpointClass {
+	return endpointClass(op.Method)
+}
+
+// path returns the path of the request relative to the base URL of the
+// client, by filling the parameters of its route.
+func (op Operation) path() string {
+	segments := strings.Split(op.Route, "/")
+	for i, seg := range segments {
+		if seg == "*" {
+			segments[i] = op.Params["*"]
+		} else if strings.HasPrefix(seg, ":") {
+			segments[i] = op.Params[seg[1:]]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// RoundTripFunc sends a request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of the requests of a client, e.g. to
+// instrument them. The operation of a request is available with
+// RequestOperation.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware to the client, and to the copies of it made afterwards
+// with WithOrgID. The first middleware added is the outermost. Middleware run
+// once the rate limiter let a request through, so they do not see the time it
+// waited.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middleware...)
+}
+
+type operationKey struct{}
+
+// RequestOperation returns the operation of a request sent by a client.
+func RequestOperation(req *http.Request) (Operation, bool) {
+	op, ok := req.Context().Value(operationKey{}).(Operation)
+	return op, ok
+}
+
+func withOperation(req *http.Request, op Operation) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), operationKey{}, op))
+}
+
+type operationNameKey struct{}
+
+// withOperationName records the name of the operation of a request built by
+// newRequest until Do describes it.
+func withOperationName(req *http.Request, name string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), operationNameKey{}, name))
+}
+
+// operation describes a request made by the client.
+func (c *Client) operation(req *http.Request) Operation {
+	requestPath := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(c.baseURL.Path, "/"))
+	route, params := matchRoute(requestPath)
+	name, _ := req.Context().Value(operationNameKey{}).(string)
+	return Operation{
+		Name:   name,
+		Method: req.Method,
+		Route:  route,
+		Params: params,
+		OrgID:  c.orgID,
+	}
+}
+
+// routes lists the templates of the routes with parameters used by the
+// client. A parameter segment starts with a colon and "*" matches the rest of
+// a path. Routes are matched in order, so literal segments come first.
+var routes = compileRoutes(
+	"/api/access-control/builtin-roles/:builtinRole/roles/:roleUid",
+	"/api/access-control/roles/:uid",
+	"/api/access-control/teams/:teamId/roles/:roleUid",
+	"/api/access-control/teams/:teamId/roles",
+	"/api/access-control/users/:userId/roles/:roleUid",
+	"/api/access-control/users/:userId/roles",
+	"/api/admin/users/:id/auth-tokens",
+	"/api/admin/users/:id/quotas",
+	"/api/admin/users/:id/:action",
+	"/api/admin/users/:id",
+	"/api/alert-notifications/:id",
+	"/api/dashboards/uid/:uid",
+	"/api/datasources/proxy/uid/:uid/*",
+	"/api/datasources/uid/:uid",
+	"/api/datasources/name/:name",
+	"/api/datasources/:id/permissions/:permissionId",
+	"/api/datasources/:id/:action",
+	"/api/datasources/:id",
+	"/api/folders/id/:id",
+	"/api/folders/:uid/move",
+	"/api/folders/:uid",
+	"/api/org/invites/:code/revoke",
+	"/api/org/users/lookup",
+	"/api/org/users/:userId",
+	"/api/orgs/name/:name",
+	"/api/orgs/:id/quotas/:target",
+	"/api/orgs/:id/users/search",
+	"/api/orgs/:id/users/:userId",
+	"/api/orgs/:id/address",
+	"/api/orgs/:id/quotas",
+	"/api/orgs/:id/users",
+	"/api/orgs/:id",
+	"/api/user/invite/complete",
+	"/api/user/invite/:code",
+	"/api/user/stars/dashboard/uid/:uid",
+	"/api/user/using/:orgId",
+	"/api/users/lookup",
+	"/api/users/search",
+	"/api/users/:id/orgs",
+	"/api/users/:id/teams",
+	"/api/users/:id",
+)
+
+func compileRoutes(templates ...string) [][]string {
+	compiled := make([][]string, len(templates))
+	for i, template := range templates {
+		compiled[i] = strings.Split(strings.Trim(template, "/"), "/")
+	}
+	return compiled
+}
+
+// matchRoute returns the route template of a path along with its parameters.
+// Paths without parameters are their own template.
+func matchRoute(requestPath string) (string, map[string]string) {
+	segments := strings.Split(strings.Trim(requestPath, "/"), "/")
+	for _, route := range routes {
+		if params, ok := matchSegments(route, segments); ok {
+			return "/" + strings.Join(route, "/"), params
+		}
+	}
+	return "/" + strings.Join(segments, "/"), map[string]string{}
+}
+
+func matchSegments(route, segments []string) (map[string]string, bool) {
+	params := map[string]string{}
+	for i, seg := range route {
+		if seg == "*" {
+			params["*"] = strings.Join(segments[i:], "/")
+			return params, len(segments) > i
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(seg, ":") {
+			params[seg[1:]] = segments[i]
+		} else if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, len(route) == len(segments)
+}
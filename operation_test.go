@@ -0,0 +1,103 @@
+package gapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMatchRoute(t *testing.T) {
+	for _, tc := range []struct {
+		path   string
+		route  string
+		params map[string]string
+	}{
+		{"/api/dashboards/uid/abc", "/api/dashboards/uid/:uid", map[string]string{"uid": "abc"}},
+		{"/api/dashboards/db", "/api/dashboards/db", map[string]string{}},
+		{"/api/users/lookup", "/api/users/lookup", map[string]string{}},
+		{"/api/users/3", "/api/users/:id", map[string]string{"id": "3"}},
+		{"/api/orgs/1/users/2", "/api/orgs/:id/users/:userId", map[string]string{"id": "1", "userId": "2"}},
+		{"/api/datasources/proxy/uid/p/api/v1/query", "/api/datasources/proxy/uid/:uid/*", map[string]string{"uid": "p", "*": "api/v1/query"}},
+	} {
+		route, params := matchRoute(tc.path)
+		if route != tc.route || !reflect.DeepEqual(params, tc.params) {
+			t.Errorf("%s: expected %s %v, got %s %v", tc.path, tc.route, tc.params, route, params)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	server, client := gapiTestTools(200, `{"id":1,"name":"Main Org."}`)
+	defer server.Close()
+
+	var ops []Operation
+	var order []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				if op, ok := RequestOperation(req); ok && name == "outer" {
+					ops = append(ops, op)
+				}
+				return next(req)
+			}
+		}
+	}
+	client.Use(record("outer"), record("inner"))
+	scoped := client.WithOrgID(2)
+	scoped.Use(record("scoped"))
+
+	if _, err := client.Org(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := scoped.DeleteDashboardByUID("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Operation{
+		{Name: "Org", Method: "GET", Route: "/api/orgs/:id", Params: map[string]string{"id": "1"}},
+		{Name: "DeleteDashboardByUID", Method: "DELETE", Route: "/api/dashboards/uid/:uid", Params: map[string]string{"uid": "abc"}, OrgID: 2},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Expected operations %+v, got %+v", expected, ops)
+	}
+	if !reflect.DeepEqual(order, []string{"outer", "inner", "outer", "inner", "scoped"}) {
+		t.Errorf("Unexpected middleware order %v", order)
+	}
+	if len(client.middleware) != 2 {
+		t.Errorf("Middleware added to a copy should not apply to the original client")
+	}
+}
+
+func TestMiddlewareExcludesRateLimitWait(t *testing.T) {
+	server, client := gapiTestTools(200, `{"id":1,"name":"Main Org."}`)
+	defer server.Close()
+
+	client.SetRateLimit(RateLimit{Limit: Limit{Rate: 50, Burst: 1}})
+	var longest time.Duration
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			defer func() {
+				if d := time.Since(start); d > longest {
+					longest = d
+				}
+			}()
+			return next(req)
+		}
+	})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Org(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("4 requests at 50 per second should take about 60ms, took %s", elapsed)
+	}
+	if longest >= 15*time.Millisecond {
+		t.Errorf("Middleware should not see the rate limiter wait, longest request took %s", longest)
+	}
+}
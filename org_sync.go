@@ -0,0 +1,202 @@
+package gapi
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SyncOrgMembersOpts configures SyncOrgMembers.
+type SyncOrgMembersOpts struct {
+	// DryRun only computes the changes without applying them.
+	DryRun bool
+	// Protected lists logins or emails of members that are never removed,
+	// e.g. the admin user the client authenticates as.
+	Protected []string
+	// KeepUnlisted leaves members that are not in the desired state alone
+	// instead of removing them.
+	KeepUnlisted bool
+}
+
+// OrgMemberChangeKind is the kind of change made to an org membership.
+type OrgMemberChangeKind string
+
+const (
+	OrgMemberAdd    OrgMemberChangeKind = "add"
+	OrgMemberUpdate OrgMemberChangeKind = "update"
+	OrgMemberRemove OrgMemberChangeKind = "remove"
+)
+
+// OrgMemberChange is a single change planned by SyncOrgMembers.
+type OrgMemberChange struct {
+	Kind OrgMemberChangeKind `json:"kind"`
+	// User is the login or email the member is known by in the desired
+	// state, or its login for removals.
+	User    string `json:"user"`
+	UserId  int64  `json:"userId,omitempty"`
+	From    Role   `json:"from,omitempty"`
+	To      Role   `json:"to,omitempty"`
+	Applied bool   `json:"applied"`
+}
+
+func (c OrgMemberChange) String() string {
+	switch c.Kind {
+	case OrgMemberAdd:
+		return fmt.Sprintf("+ %s (%s)", c.User, c.To)
+	case OrgMemberUpdate:
+		return fmt.Sprintf("~ %s (%s -> %s)", c.User, c.From, c.To)
+	default:
+		return fmt.Sprintf("- %s (%s)", c.User, c.From)
+	}
+}
+
+// SyncOrgMembersReport describes what SyncOrgMembers did, or would do in dry
+// run mode.
+type SyncOrgMembersReport struct {
+	OrgId   int64             `json:"orgId"`
+	DryRun  bool              `json:"dryRun"`
+	Changes []OrgMemberChange `json:"changes"`
+	// Unchanged lists the desired members that already have the right role.
+	Unchanged []string `json:"unchanged"`
+	// Protected lists members that would have been removed but are protected.
+	Protected []string `json:"protected"`
+}
+
+// String renders the report with one line per change.
+func (r *SyncOrgMembersReport) String() string {
+	var buf bytes.Buffer
+	mode := ""
+	if r.DryRun {
+		mode = " (dry run)"
+	}
+	fmt.Fprintf(&buf, "org %d: %d changes, %d unchanged, %d protected%s\n",
+		r.OrgId, len(r.Changes), len(r.Unchanged), len(r.Protected), mode)
+	for _, change := range r.Changes {
+		fmt.Fprintln(&buf, change)
+	}
+	return buf.String()
+}
+
+// SyncOrgMembers converges the members of an organization to desired, which
+// maps logins or emails to their role. Members missing from desired are
+// removed unless protected or opts.KeepUnlisted is set.
+//
+// Changes are applied in the order of the report: additions, then role
+// updates, then removals. The first failing change stops the sync and its
+// error is returned along with the report, in which only the changes made so
+// far are marked as applied.
+func (c *Client) SyncOrgMembers(orgId int64, desired map[string]Role, opts SyncOrgMembersOpts) (*SyncOrgMembersReport, error) {
+	for user, role := range desired {
+		if err := role.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "Invalid role for %s", user)
+		}
+	}
+
+	current, err := c.OrgUsers(orgId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list members of org %d", orgId)
+	}
+
+	report := planOrgMembers(current, desired, opts)
+	report.OrgId = orgId
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for i := range report.Changes {
+		change := &report.Changes[i]
+		switch change.Kind {
+		case OrgMemberAdd:
+			err = c.AddOrgUser(orgId, change.User, change.To)
+		case OrgMemberUpdate:
+			err = c.UpdateOrgUser(orgId, change.UserId, change.To)
+		case OrgMemberRemove:
+			err = c.RemoveOrgUser(orgId, change.UserId)
+		}
+		if err != nil {
+			return report, errors.Wrapf(err, "Failed to %s %s", change.Kind, change.User)
+		}
+		change.Applied = true
+	}
+	return report, nil
+}
+
+// planOrgMembers computes the changes turning current into desired.
+func planOrgMembers(current []OrgUser, desired map[string]Role, opts SyncOrgMembersOpts) *SyncOrgMembersReport {
+	report := &SyncOrgMembersReport{
+		DryRun:    opts.DryRun,
+		Changes:   make([]OrgMemberChange, 0),
+		Unchanged: make([]string, 0),
+		Protected: make([]string, 0),
+	}
+
+	members := make(map[string]*OrgUser, 2*len(current))
+	for i := range current {
+		user := &current[i]
+		members[strings.ToLower(user.Login)] = user
+		if user.Email != "" {
+			members[strings.ToLower(user.Email)] = user
+		}
+	}
+	protected := make(map[string]bool, len(opts.Protected))
+	for _, p := range opts.Protected {
+		protected[strings.ToLower(p)] = true
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var adds, updates, removes []OrgMemberChange
+	wanted := make(map[int64]bool, len(desired))
+	for _, key := range keys {
+		role := desired[key]
+		user, ok := members[strings.ToLower(key)]
+		if !ok {
+			adds = append(adds, OrgMemberChange{Kind: OrgMemberAdd, User: key, To: role})
+			continue
+		}
+		wanted[user.UserId] = true
+		if user.Role == role {
+			report.Unchanged = append(report.Unchanged, key)
+			continue
+		}
+		updates = append(updates, OrgMemberChange{
+			Kind:   OrgMemberUpdate,
+			User:   key,
+			UserId: user.UserId,
+			From:   user.Role,
+			To:     role,
+		})
+	}
+
+	if !opts.KeepUnlisted {
+		for _, user := range current {
+			if wanted[user.UserId] {
+				continue
+			}
+			if protected[strings.ToLower(user.Login)] || (user.Email != "" && protected[strings.ToLower(user.Email)]) {
+				report.Protected = append(report.Protected, user.Login)
+				continue
+			}
+			removes = append(removes, OrgMemberChange{
+				Kind:   OrgMemberRemove,
+				User:   user.Login,
+				UserId: user.UserId,
+				From:   user.Role,
+			})
+		}
+		sort.Slice(removes, func(i, j int) bool { return removes[i].User < removes[j].User })
+		sort.Strings(report.Protected)
+	}
+
+	report.Changes = append(report.Changes, adds...)
+	report.Changes = append(report.Changes, updates...)
+	report.Changes = append(report.Changes, removes...)
+	return report
+}
@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const syncOrgUsersJSON = `[{"orgId":1,"userId":1,"email":"admin@localhost","login":"admin","role":"Admin"},{"orgId":1,"userId":2,"email":"alice@example.com","login":"alice","role":"Viewer"},{"orgId":1,"userId":3,"email":"bob@example.com","login":"bob","role":"Editor"},{"orgId":1,"userId":4,"email":"carol@example.com","login":"carol","role":"Viewer"}]`
+
+func TestSyncOrgMembers(t *testing.T) {
+	requests := []string{}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, syncOrgUsersJSON)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"message":"ok"}`)
+	}))
+	defer server.Close()
+
+	desired := map[string]Role{
+		"Alice@example.com": "Editor",
+		"carol":             "Viewer",
+		"dave@example.com":  "Viewer",
+	}
+	opts := SyncOrgMembersOpts{DryRun: true, Protected: []string{"admin"}}
+
+	report, err := client.SyncOrgMembers(1, desired, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(report)
+
+	expected := []OrgMemberChange{
+		{Kind: OrgMemberAdd, User: "dave@example.com", To: "Viewer"},
+		{Kind: OrgMemberUpdate, User: "Alice@example.com", UserId: 2, From: "Viewer", To: "Editor"},
+		{Kind: OrgMemberRemove, User: "bob", UserId: 3, From: "Editor"},
+	}
+	if !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Unexpected plan %v", report.Changes)
+	}
+	if !reflect.DeepEqual(report.Unchanged, []string{"carol"}) || !reflect.DeepEqual(report.Protected, []string{"admin"}) {
+		t.Errorf("Unexpected unchanged %v or protected %v members", report.Unchanged, report.Protected)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Dry run should not change anything, got %v", requests)
+	}
+
+	opts.DryRun = false
+	report, err = client.SyncOrgMembers(1, desired, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRequests := []string{"POST /api/orgs/1/users", "PATCH /api/orgs/1/users/2", "DELETE /api/orgs/1/users/3"}
+	if !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("Unexpected requests %v", requests)
+	}
+	for _, change := range report.Changes {
+		if !change.Applied {
+			t.Errorf("Change %s should be applied", change)
+		}
+	}
+}
+
+func TestSyncOrgMembersKeepUnlisted(t *testing.T) {
+	server, client := gapiTestTools(200, syncOrgUsersJSON)
+	defer server.Close()
+
+	report, err := client.SyncOrgMembers(1, map[string]Role{"alice": "Viewer"}, SyncOrgMembersOpts{DryRun: true, KeepUnlisted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("Unlisted members should be kept, got %v", report.Changes)
+	}
+}
+
+func TestSyncOrgMembersError(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, syncOrgUsersJSON)
+			return
+		}
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"message":"User not found"}`)
+	}))
+	defer server.Close()
+
+	report, err := client.SyncOrgMembers(1, map[string]Role{"dave": "Viewer"}, SyncOrgMembersOpts{KeepUnlisted: true})
+	if err == nil {
+		t.Fatal("Expected sync to fail")
+	}
+	if len(report.Changes) != 1 || report.Changes[0].Applied {
+		t.Errorf("Failed change should not be marked applied, got %v", report.Changes)
+	}
+}
@@ -3,22 +3,94 @@ package gapi
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 type OrgUser struct {
-	OrgId  int64  `json:"orgId"`
-	UserId int64  `json:"userId"`
-	Email  string `json:"email"`
-	Login  string `json:"login"`
-	Role   string `json:"role"`
+	OrgId         int64     `json:"orgId"`
+	UserId        int64     `json:"userId"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name,omitempty"`
+	AvatarUrl     string    `json:"avatarUrl,omitempty"`
+	Login         string    `json:"login"`
+	Role          Role      `json:"role"`
+	LastSeenAt    time.Time `json:"lastSeenAt"`
+	LastSeenAtAge string    `json:"lastSeenAtAge,omitempty"`
+	IsDisabled    bool      `json:"isDisabled,omitempty"`
+	AuthLabels    []string  `json:"authLabels,omitempty"`
+}
+
+// OrgUserSearchPage is a single page of SearchOrgUsers results.
+type OrgUserSearchPage struct {
+	TotalCount int64     `json:"totalCount"`
+	OrgUsers   []OrgUser `json:"orgUsers"`
+	Page       int       `json:"page"`
+	PerPage    int       `json:"perPage"`
+}
+
+// OrgUserLookup is the short form of an org user returned by
+// CurrentOrgUsersLookup.
+type OrgUserLookup struct {
+	UserId    int64  `json:"userId"`
+	Login     string `json:"login"`
+	AvatarUrl string `json:"avatarUrl"`
+}
+
+// InviteStatus is the state of an invitation to join an organization.
+type InviteStatus string
+
+const (
+	InviteStatusSignUpStarted InviteStatus = "SignUpStarted"
+	InviteStatusPending       InviteStatus = "InvitePending"
+	InviteStatusCompleted     InviteStatus = "Completed"
+	InviteStatusRevoked       InviteStatus = "Revoked"
+	InviteStatusExpired       InviteStatus = "Expired"
+)
+
+// OrgInvite is an invitation of a possibly not yet existing user to the
+// current organization.
+type OrgInvite struct {
+	Id             int64        `json:"id"`
+	OrgId          int64        `json:"orgId"`
+	Name           string       `json:"name"`
+	Email          string       `json:"email"`
+	Role           Role         `json:"role"`
+	InvitedByLogin string       `json:"invitedByLogin"`
+	InvitedByEmail string       `json:"invitedByEmail"`
+	InvitedByName  string       `json:"invitedByName"`
+	Code           string       `json:"code"`
+	Status         InviteStatus `json:"status"`
+	Url            string       `json:"url"`
+	EmailSent      bool         `json:"emailSent"`
+	CreatedOn      time.Time    `json:"createdOn"`
+}
+
+// OrgInviteOpts is the body of a request creating an invite.
+type OrgInviteOpts struct {
+	LoginOrEmail string `json:"loginOrEmail"`
+	Name         string `json:"name,omitempty"`
+	Role         Role   `json:"role"`
+	SendEmail    bool   `json:"sendEmail"`
+}
+
+// CompleteInviteOpts is the body of a request accepting an invite and signing
+// up the invited user.
+type CompleteInviteOpts struct {
+	InviteCode      string `json:"inviteCode"`
+	Username        string `json:"username"`
+	Email           string `json:"email"`
+	Name            string `json:"name,omitempty"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
 }
 
 func (c *Client) OrgUsers(orgId int64) ([]OrgUser, error) {
 	users := make([]OrgUser, 0)
-	req, err := c.newRequest("GET", fmt.Sprintf("/api/orgs/%d/users", orgId), nil, nil)
+	req, err := c.newRequest("OrgUsers", "GET", fmt.Sprintf("/api/orgs/%d/users", orgId), nil, nil)
 	if err != nil {
 		return users, err
 	}
@@ -27,7 +99,10 @@ func (c *Client) OrgUsers(orgId int64) ([]OrgUser, error) {
 		return users, err
 	}
 	if resp.StatusCode != 200 {
-		return users, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return users, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -40,13 +115,16 @@ func (c *Client) OrgUsers(orgId int64) ([]OrgUser, error) {
 	return users, err
 }
 
-func (c *Client) AddOrgUser(orgId int64, user, role string) error {
+func (c *Client) AddOrgUser(orgId int64, user string, role Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
 	dataMap := map[string]string{
 		"loginOrEmail": user,
-		"role":         role,
+		"role":         string(role),
 	}
 	data, err := json.Marshal(dataMap)
-	req, err := c.newRequest("POST", fmt.Sprintf("/api/orgs/%d/users", orgId), nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("AddOrgUser", "POST", fmt.Sprintf("/api/orgs/%d/users", orgId), nil, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -55,17 +133,23 @@ func (c *Client) AddOrgUser(orgId int64, user, role string) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	return err
 }
 
-func (c *Client) UpdateOrgUser(orgId, userId int64, role string) error {
+func (c *Client) UpdateOrgUser(orgId, userId int64, role Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
 	dataMap := map[string]string{
-		"role": role,
+		"role": string(role),
 	}
 	data, err := json.Marshal(dataMap)
-	req, err := c.newRequest("PATCH", fmt.Sprintf("/api/orgs/%d/users/%d", orgId, userId), nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("UpdateOrgUser", "PATCH", fmt.Sprintf("/api/orgs/%d/users/%d", orgId, userId), nil, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -74,13 +158,202 @@ func (c *Client) UpdateOrgUser(orgId, userId int64, role string) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	return err
 }
 
 func (c *Client) RemoveOrgUser(orgId, userId int64) error {
-	req, err := c.newRequest("DELETE", fmt.Sprintf("/api/orgs/%d/users/%d", orgId, userId), nil, nil)
+	req, err := c.newRequest("RemoveOrgUser", "DELETE", fmt.Sprintf("/api/orgs/%d/users/%d", orgId, userId), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	return err
+}
+
+// SearchOrgUsers returns a page of the members of an organization whose
+// login, email or name match query. Pages start at 1.
+func (c *Client) SearchOrgUsers(orgId int64, query string, perPage, page int) (*OrgUserSearchPage, error) {
+	params := url.Values{}
+	if query != "" {
+		params.Add("query", query)
+	}
+	params.Add("perpage", strconv.Itoa(perPage))
+	params.Add("page", strconv.Itoa(page))
+	result := &OrgUserSearchPage{}
+	err := c.sendJSON("SearchOrgUsers", "GET", fmt.Sprintf("/api/orgs/%d/users/search", orgId), params, nil, result)
+	return result, err
+}
+
+// The CurrentOrg methods below manage the members of the current organization
+// of the user, or of the organization the client is scoped to with WithOrgID.
+// Unlike the methods above they only require org admin rights.
+
+func (c *Client) CurrentOrgUsers() ([]OrgUser, error) {
+	users := make([]OrgUser, 0)
+	err := c.getJSON("CurrentOrgUsers", "/api/org/users", &users)
+	return users, err
+}
+
+// CurrentOrgUsersLookup returns up to limit members matching query in their
+// short form. It is available to every member of the organization.
+func (c *Client) CurrentOrgUsersLookup(query string, limit int) ([]OrgUserLookup, error) {
+	users := make([]OrgUserLookup, 0)
+	params := url.Values{}
+	if query != "" {
+		params.Add("query", query)
+	}
+	if limit > 0 {
+		params.Add("limit", strconv.Itoa(limit))
+	}
+	err := c.sendJSON("CurrentOrgUsersLookup", "GET", "/api/org/users/lookup", params, nil, &users)
+	return users, err
+}
+
+func (c *Client) AddCurrentOrgUser(user string, role Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	dataMap := map[string]string{
+		"loginOrEmail": user,
+		"role":         string(role),
+	}
+	return c.sendJSON("AddCurrentOrgUser", "POST", "/api/org/users", nil, dataMap, nil)
+}
+
+func (c *Client) UpdateCurrentOrgUser(userId int64, role Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	dataMap := map[string]string{
+		"role": string(role),
+	}
+	return c.sendJSON("UpdateCurrentOrgUser", "PATCH", fmt.Sprintf("/api/org/users/%d", userId), nil, dataMap, nil)
+}
+
+func (c *Client) RemoveCurrentOrgUser(userId int64) error {
+	return c.sendJSON("RemoveCurrentOrgUser", "DELETE", fmt.Sprintf("/api/org/users/%d", userId), nil, nil, nil)
+}
+
+// CreateOrgInvite invites a user to the current organization. An existing
+// user is added to the organization right away instead.
+func (c *Client) CreateOrgInvite(invite OrgInviteOpts) error {
+	if err := invite.Role.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(invite)
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest("CreateOrgInvite", "POST", "/api/org/invites", nil, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	return err
+}
+
+// OrgInvites lists the pending invites of the current organization.
+func (c *Client) OrgInvites() ([]OrgInvite, error) {
+	invites := make([]OrgInvite, 0)
+	req, err := c.newRequest("OrgInvites", "GET", "/api/org/invites", nil, nil)
+	if err != nil {
+		return invites, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return invites, err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return invites, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return invites, err
+	}
+	err = json.Unmarshal(data, &invites)
+	return invites, err
+}
+
+func (c *Client) RevokeOrgInvite(code string) error {
+	req, err := c.newRequest("RevokeOrgInvite", "DELETE", fmt.Sprintf("/api/org/invites/%s/revoke", code), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	return err
+}
+
+// OrgInviteByCode returns the invite with the given code. It does not require
+// authentication.
+func (c *Client) OrgInviteByCode(code string) (OrgInvite, error) {
+	invite := OrgInvite{}
+	req, err := c.newRequest("OrgInviteByCode", "GET", fmt.Sprintf("/api/user/invite/%s", code), nil, nil)
+	if err != nil {
+		return invite, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return invite, err
+	}
+	if resp.StatusCode != 200 {
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return invite, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return invite, err
+	}
+	err = json.Unmarshal(data, &invite)
+	if invite.Code == "" {
+		invite.Code = code
+	}
+	return invite, err
+}
+
+// CompleteOrgInvite accepts an invite, creating the invited user.
+func (c *Client) CompleteOrgInvite(opts CompleteInviteOpts) error {
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest("CompleteOrgInvite", "POST", "/api/user/invite/complete", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -89,7 +362,10 @@ func (c *Client) RemoveOrgUser(orgId, userId int64) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	return err
 }
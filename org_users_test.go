@@ -1,8 +1,11 @@
 package gapi
 
 import (
-	"github.com/gobs/pretty"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/gobs/pretty"
 )
 
 const (
@@ -10,6 +13,15 @@ const (
 	addOrgUserJSON    = `{"message":"User added to organization"}`
 	updateOrgUserJSON = `{"message":"Organization user updated"}`
 	removeOrgUserJSON = `{"message":"User removed from organization"}`
+
+	getOrgInvitesJSON     = `[{"id":1,"orgId":1,"name":"","email":"ext@example.com","role":"Viewer","invitedByLogin":"admin","invitedByEmail":"admin@localhost","invitedByName":"","code":"abc123","status":"InvitePending","url":"http://localhost:3000/invite/abc123","emailSent":true,"createdOn":"2019-03-05T21:22:54+01:00"}]`
+	createOrgInviteJSON   = `{"message":"Invited ext@example.com to Main Org."}`
+	revokeOrgInviteJSON   = `{"message":"Invite revoked"}`
+	completeOrgInviteJSON = `{"message":"User created and logged in"}`
+
+	searchOrgUsersJSON       = `{"totalCount":3,"orgUsers":[{"orgId":1,"userId":2,"email":"ldap@localhost","name":"LDAP User","login":"ldap","role":"Viewer","authLabels":["LDAP"]}],"page":2,"perPage":2}`
+	lookupOrgUsersJSON       = `[{"userId":1,"login":"admin","avatarUrl":"/avatar/46d229b033af06a191ff2267bca9ae56"}]`
+	currentOrgUserActionJSON = `{"message":"Organization user updated"}`
 )
 
 func TestOrgUsers(t *testing.T) {
@@ -25,14 +37,17 @@ func TestOrgUsers(t *testing.T) {
 	t.Log(pretty.PrettyFormat(resp))
 
 	user := OrgUser{
-		OrgId:  1,
-		UserId: 1,
-		Email:  "admin@localhost",
-		Login:  "admin",
-		Role:   "Admin",
+		OrgId:         1,
+		UserId:        1,
+		Email:         "admin@localhost",
+		AvatarUrl:     "/avatar/46d229b033af06a191ff2267bca9ae56",
+		Login:         "admin",
+		Role:          "Admin",
+		LastSeenAt:    time.Date(2018, 6, 28, 14, 16, 11, 0, time.UTC),
+		LastSeenAtAge: "< 1m",
 	}
 
-	if resp[0] != user {
+	if !reflect.DeepEqual(resp[0], user) {
 		t.Error("Not correctly parsing returned organization users.")
 	}
 }
@@ -41,7 +56,7 @@ func TestAddOrgUser(t *testing.T) {
 	server, client := gapiTestTools(200, addOrgUserJSON)
 	defer server.Close()
 
-	orgId, user, role := int64(1), "admin@localhost", "Admin"
+	orgId, user, role := int64(1), "admin@localhost", RoleAdmin
 
 	err := client.AddOrgUser(orgId, user, role)
 	if err != nil {
@@ -53,7 +68,7 @@ func TestUpdateOrgUser(t *testing.T) {
 	server, client := gapiTestTools(200, updateOrgUserJSON)
 	defer server.Close()
 
-	orgId, userId, role := int64(1), int64(1), "Editor"
+	orgId, userId, role := int64(1), int64(1), RoleEditor
 
 	err := client.UpdateOrgUser(orgId, userId, role)
 	if err != nil {
@@ -72,3 +87,122 @@ func TestRemoveOrgUser(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestCreateOrgInvite(t *testing.T) {
+	server, client := gapiTestTools(200, createOrgInviteJSON)
+	defer server.Close()
+
+	err := client.CreateOrgInvite(OrgInviteOpts{LoginOrEmail: "ext@example.com", Role: "Viewer", SendEmail: true})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOrgInvites(t *testing.T) {
+	server, client := gapiTestTools(200, getOrgInvitesJSON)
+	defer server.Close()
+
+	resp, err := client.OrgInvites()
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	if len(resp) != 1 || resp[0].Code != "abc123" || resp[0].Status != InviteStatusPending {
+		t.Error("Not correctly parsing returned invites.")
+	}
+}
+
+func TestRevokeOrgInvite(t *testing.T) {
+	server, client := gapiTestTools(200, revokeOrgInviteJSON)
+	defer server.Close()
+
+	err := client.RevokeOrgInvite("abc123")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompleteOrgInvite(t *testing.T) {
+	server, client := gapiTestTools(200, completeOrgInviteJSON)
+	defer server.Close()
+
+	err := client.CompleteOrgInvite(CompleteInviteOpts{
+		InviteCode:      "abc123",
+		Username:        "ext",
+		Email:           "ext@example.com",
+		Password:        "password",
+		ConfirmPassword: "password",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSearchOrgUsers(t *testing.T) {
+	server, client := gapiTestTools(200, searchOrgUsersJSON)
+	defer server.Close()
+
+	resp, err := client.SearchOrgUsers(int64(1), "", 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	if resp.TotalCount != 3 || resp.Page != 2 || len(resp.OrgUsers) != 1 {
+		t.Fatal("Not correctly parsing returned org user search.")
+	}
+	if resp.OrgUsers[0].Name != "LDAP User" || resp.OrgUsers[0].AuthLabels[0] != "LDAP" {
+		t.Error("Not correctly parsing returned org user.")
+	}
+}
+
+func TestCurrentOrgUsers(t *testing.T) {
+	server, client := gapiTestTools(200, getOrgUsersJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentOrgUsers()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 1 || resp[0].Login != "admin" {
+		t.Error("Not correctly parsing returned organization users.")
+	}
+}
+
+func TestCurrentOrgUsersLookup(t *testing.T) {
+	server, client := gapiTestTools(200, lookupOrgUsersJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentOrgUsersLookup("adm", 10)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 1 || resp[0].UserId != 1 || resp[0].Login != "admin" {
+		t.Error("Not correctly parsing returned user lookup.")
+	}
+}
+
+func TestUpdateCurrentOrgUser(t *testing.T) {
+	server, client := gapiTestTools(200, currentOrgUserActionJSON)
+	defer server.Close()
+
+	err := client.UpdateCurrentOrgUser(int64(1), "Editor")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRemoveCurrentOrgUser(t *testing.T) {
+	server, client := gapiTestTools(200, currentOrgUserActionJSON)
+	defer server.Close()
+
+	err := client.RemoveCurrentOrgUser(int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+}
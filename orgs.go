@@ -3,20 +3,39 @@ package gapi
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 )
 
 type Org struct {
-	Id   int64  `json:"id"`
-	Name string `json:"name"`
+	Id      int64      `json:"id"`
+	Name    string     `json:"name"`
+	Address OrgAddress `json:"address"`
+}
+
+type OrgAddress struct {
+	Address1 string `json:"address1"`
+	Address2 string `json:"address2"`
+	City     string `json:"city"`
+	ZipCode  string `json:"zipCode"`
+	State    string `json:"state"`
+	Country  string `json:"country"`
+}
+
+// Preferences are the display defaults of an organization or a user. Empty
+// values fall back to the server defaults.
+type Preferences struct {
+	Theme            string `json:"theme"`
+	HomeDashboardId  int64  `json:"homeDashboardId,omitempty"`
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+	Timezone         string `json:"timezone"`
+	WeekStart        string `json:"weekStart"`
 }
 
 func (c *Client) Orgs() ([]Org, error) {
 	orgs := make([]Org, 0)
 
-	req, err := c.newRequest("GET", "/api/orgs/", nil, nil)
+	req, err := c.newRequest("Orgs", "GET", "/api/orgs/", nil, nil)
 	if err != nil {
 		return orgs, err
 	}
@@ -25,7 +44,10 @@ func (c *Client) Orgs() ([]Org, error) {
 		return orgs, err
 	}
 	if resp.StatusCode != 200 {
-		return orgs, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return orgs, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -37,7 +59,7 @@ func (c *Client) Orgs() ([]Org, error) {
 
 func (c *Client) OrgByName(name string) (Org, error) {
 	org := Org{}
-	req, err := c.newRequest("GET", fmt.Sprintf("/api/orgs/name/%s", name), nil, nil)
+	req, err := c.newRequest("OrgByName", "GET", fmt.Sprintf("/api/orgs/name/%s", name), nil, nil)
 	if err != nil {
 		return org, err
 	}
@@ -46,7 +68,10 @@ func (c *Client) OrgByName(name string) (Org, error) {
 		return org, err
 	}
 	if resp.StatusCode != 200 {
-		return org, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return org, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -58,7 +83,7 @@ func (c *Client) OrgByName(name string) (Org, error) {
 
 func (c *Client) Org(id int64) (Org, error) {
 	org := Org{}
-	req, err := c.newRequest("GET", fmt.Sprintf("/api/orgs/%d", id), nil, nil)
+	req, err := c.newRequest("Org", "GET", fmt.Sprintf("/api/orgs/%d", id), nil, nil)
 	if err != nil {
 		return org, err
 	}
@@ -67,7 +92,10 @@ func (c *Client) Org(id int64) (Org, error) {
 		return org, err
 	}
 	if resp.StatusCode != 200 {
-		return org, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return org, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -83,7 +111,7 @@ func (c *Client) NewOrg(name string) (int64, error) {
 	}
 	data, err := json.Marshal(dataMap)
 	id := int64(0)
-	req, err := c.newRequest("POST", "/api/orgs", nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("NewOrg", "POST", "/api/orgs", nil, bytes.NewBuffer(data))
 	if err != nil {
 		return id, err
 	}
@@ -92,7 +120,10 @@ func (c *Client) NewOrg(name string) (int64, error) {
 		return id, err
 	}
 	if resp.StatusCode != 200 {
-		return id, errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return id, &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	data, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -114,7 +145,7 @@ func (c *Client) UpdateOrg(id int64, name string) error {
 		"name": name,
 	}
 	data, err := json.Marshal(dataMap)
-	req, err := c.newRequest("PUT", fmt.Sprintf("/api/orgs/%d", id), nil, bytes.NewBuffer(data))
+	req, err := c.newRequest("UpdateOrg", "PUT", fmt.Sprintf("/api/orgs/%d", id), nil, bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -123,13 +154,16 @@ func (c *Client) UpdateOrg(id int64, name string) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	return err
 }
 
 func (c *Client) DeleteOrg(id int64) error {
-	req, err := c.newRequest("DELETE", fmt.Sprintf("/api/orgs/%d", id), nil, nil)
+	req, err := c.newRequest("DeleteOrg", "DELETE", fmt.Sprintf("/api/orgs/%d", id), nil, nil)
 	if err != nil {
 		return err
 	}
@@ -138,7 +172,65 @@ func (c *Client) DeleteOrg(id int64) error {
 		return err
 	}
 	if resp.StatusCode != 200 {
-		return errors.New(resp.Status)
+		var gmsg GrafanaErrorMessage
+		dec := json.NewDecoder(resp.Body)
+		dec.Decode(&gmsg)
+		return &GrafanaError{resp.StatusCode, fmt.Sprint(gmsg)}
 	}
 	return err
 }
+
+func (c *Client) UpdateOrgAddress(id int64, address OrgAddress) error {
+	return c.sendJSON("UpdateOrgAddress", "PUT", fmt.Sprintf("/api/orgs/%d/address", id), nil, address, nil)
+}
+
+func (c *Client) OrgQuotas(id int64) ([]Quota, error) {
+	quotas := make([]Quota, 0)
+	err := c.getJSON("OrgQuotas", fmt.Sprintf("/api/orgs/%d/quotas", id), &quotas)
+	return quotas, err
+}
+
+// UpdateOrgQuota sets the limit of the quota of an organization for target,
+// e.g. "user", "dashboard" or "data_source". A limit of -1 means unlimited.
+func (c *Client) UpdateOrgQuota(id int64, target string, limit int64) error {
+	body := map[string]int64{
+		"limit": limit,
+	}
+	return c.sendJSON("UpdateOrgQuota", "PUT", fmt.Sprintf("/api/orgs/%d/quotas/%s", id, target), nil, body, nil)
+}
+
+// The methods below act on the current organization of the user, or on the
+// organization the client is scoped to with WithOrgID.
+
+func (c *Client) CurrentOrg() (Org, error) {
+	org := Org{}
+	err := c.getJSON("CurrentOrg", "/api/org", &org)
+	return org, err
+}
+
+func (c *Client) UpdateCurrentOrg(name string) error {
+	dataMap := map[string]string{
+		"name": name,
+	}
+	return c.sendJSON("UpdateCurrentOrg", "PUT", "/api/org", nil, dataMap, nil)
+}
+
+func (c *Client) UpdateCurrentOrgAddress(address OrgAddress) error {
+	return c.sendJSON("UpdateCurrentOrgAddress", "PUT", "/api/org/address", nil, address, nil)
+}
+
+func (c *Client) OrgPreferences() (Preferences, error) {
+	prefs := Preferences{}
+	err := c.getJSON("OrgPreferences", "/api/org/preferences", &prefs)
+	return prefs, err
+}
+
+func (c *Client) UpdateOrgPreferences(prefs Preferences) error {
+	return c.sendJSON("UpdateOrgPreferences", "PUT", "/api/org/preferences", nil, prefs, nil)
+}
+
+func (c *Client) CurrentOrgQuotas() ([]Quota, error) {
+	quotas := make([]Quota, 0)
+	err := c.getJSON("CurrentOrgQuotas", "/api/org/quotas", &quotas)
+	return quotas, err
+}
@@ -11,6 +11,11 @@ const (
 	createdOrgJSON = `{"message":"Organization created","orgId":1}`
 	updatedOrgJSON = `{"message":"Organization updated"}`
 	deletedOrgJSON = `{"message":"Organization deleted"}`
+
+	getOrgWithAddressJSON = `{"id":1,"name":"Main Org.","address":{"address1":"1 Main St","address2":"","city":"Stockholm","zipCode":"111 22","state":"","country":"Sweden"}}`
+	getOrgPreferencesJSON = `{"theme":"dark","homeDashboardId":0,"homeDashboardUID":"home","timezone":"utc","weekStart":"monday"}`
+	getOrgQuotasJSON      = `[{"org_id":1,"target":"user","limit":10,"used":4},{"org_id":1,"target":"dashboard","limit":-1,"used":12}]`
+	updatedJSON           = `{"message":"Updated"}`
 )
 
 func TestOrgs(t *testing.T) {
@@ -101,3 +106,92 @@ func TestDeleteOrg(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestOrgAddress(t *testing.T) {
+	server, client := gapiTestTools(200, getOrgWithAddressJSON)
+	defer server.Close()
+
+	resp, err := client.Org(int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if resp.Address.City != "Stockholm" || resp.Address.Country != "Sweden" {
+		t.Error("Not correctly parsing returned organization address.")
+	}
+}
+
+func TestUpdateOrgAddress(t *testing.T) {
+	server, client := gapiTestTools(200, updatedJSON)
+	defer server.Close()
+
+	err := client.UpdateOrgAddress(int64(1), OrgAddress{City: "Stockholm"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCurrentOrg(t *testing.T) {
+	server, client := gapiTestTools(200, getOrgJSON)
+	defer server.Close()
+
+	resp, err := client.CurrentOrg()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if resp.Id != 1 || resp.Name != "Main Org." {
+		t.Error("Not correctly parsing returned organization.")
+	}
+}
+
+func TestOrgPreferences(t *testing.T) {
+	server, client := gapiTestTools(200, getOrgPreferencesJSON)
+	defer server.Close()
+
+	resp, err := client.WithOrgID(2).OrgPreferences()
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	prefs := Preferences{Theme: "dark", HomeDashboardUID: "home", Timezone: "utc", WeekStart: "monday"}
+	if resp != prefs {
+		t.Error("Not correctly parsing returned preferences.")
+	}
+}
+
+func TestUpdateOrgPreferences(t *testing.T) {
+	server, client := gapiTestTools(200, updatedJSON)
+	defer server.Close()
+
+	err := client.UpdateOrgPreferences(Preferences{Theme: "light"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOrgQuotas(t *testing.T) {
+	server, client := gapiTestTools(200, getOrgQuotasJSON)
+	defer server.Close()
+
+	resp, err := client.OrgQuotas(int64(1))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 2 || resp[1] != (Quota{OrgId: 1, Target: "dashboard", Limit: -1, Used: 12}) {
+		t.Error("Not correctly parsing returned quotas.")
+	}
+}
+
+func TestUpdateOrgQuota(t *testing.T) {
+	server, client := gapiTestTools(200, updatedJSON)
+	defer server.Close()
+
+	err := client.UpdateOrgQuota(int64(1), "user", 20)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// queryRoutes are the routes of requests that read despite not being GETs.
+// They are sent in dry-run mode. Requests through the datasource proxy are
+// not among them, as the proxied API may write, e.g. to delete series.
+var queryRoutes = map[string]bool{
+	"/api/ds/query": true,
+}
+
+// mutates reports whether the operation changes something on the server.
+func (op Operation) mutates() bool {
+	return op.Class() == EndpointWrite && !queryRoutes[op.Route]
+}
+
+// PlannedRequest is a write a client in dry-run mode did not send.
+type PlannedRequest struct {
+	// Operation is the Client method that would have sent the request.
+	Operation string `json:"operation,omitempty"`
+	Method    string `json:"method"`
+	// Path is the path of the request, with its query, relative to the
+	// base URL of the client.
+	Path  string `json:"path"`
+	OrgID int64  `json:"orgId,omitempty"`
+	// Body is the JSON body of the request with its secrets redacted, or
+	// the body as a string when it is not JSON.
+	Body interface{} `json:"body,omitempty"`
+}
+
+// Plan lists the writes of a client in dry-run mode, in the order they were
+// attempted.
+type Plan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// Requests returns the planned writes.
+func (p *Plan) Requests() []PlannedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedRequest(nil), p.requests...)
+}
+
+// Reset empties the plan.
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = nil
+}
+
+// MarshalJSON renders the plan as a JSON array of requests.
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	requests := p.Requests()
+	if requests == nil {
+		requests = []PlannedRequest{}
+	}
+	return json.Marshal(requests)
+}
+
+// String renders the plan as text, one numbered write per line followed by
+// its body, if any.
+func (p *Plan) String() string {
+	requests := p.Requests()
+	if len(requests) == 0 {
+		return "No changes.\n"
+	}
+	var buf bytes.Buffer
+	for i, r := range requests {
+		fmt.Fprintf(&buf, "%d. %s %s", i+1, r.Method, r.Path)
+		if r.Operation != "" {
+			fmt.Fprintf(&buf, " (%s)", r.Operation)
+		}
+		if r.OrgID != 0 {
+			fmt.Fprintf(&buf, " in org %d", r.OrgID)
+		}
+		fmt.Fprintln(&buf)
+		if r.Body != nil {
+			body, _ := json.MarshalIndent(r.Body, "   ", "  ")
+			fmt.Fprintf(&buf, "   %s\n", body)
+		}
+	}
+	return buf.String()
+}
+
+func (p *Plan) add(req *http.Request, op Operation) error {
+	planned := PlannedRequest{
+		Operation: op.Name,
+		Method:    req.Method,
+		Path:      op.path(),
+		OrgID:     op.OrgID,
+	}
+	if req.URL.RawQuery != "" {
+		planned.Path += "?" + req.URL.RawQuery
+	}
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+		if body, ok := redactJSON(data); ok {
+			planned.Body = body
+		} else if len(data) > 0 {
+			planned.Body = string(data)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, planned)
+	return nil
+}
+
+// plannedResponse answers a write in dry-run mode as if it succeeded with an
+// empty object, so that methods return zero values and a nil error.
+func plannedResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    200,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(strings.NewReader("{}")),
+		ContentLength: 2,
+		Request:       req,
+	}
+}
+
+// DryRun returns a copy of the client in dry-run mode, along with its plan.
+// Reads are sent as usual, while writes are added to the plan instead and
+// answered as if they succeeded, with zero values. Copies made with
+// WithOrgID share the plan.
+//
+// Methods that depend on the result of a write, e.g. the id of a created
+// resource, see zero values, so a plan may stop short of what a real run
+// would do.
+func (c *Client) DryRun() (*Client, *Plan) {
+	clone := *c
+	clone.plan = &Plan{}
+	return &clone, clone.plan
+}
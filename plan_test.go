@@ -0,0 +1,117 @@
+package gapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Unexpected %s %s in dry-run mode", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{"meta":{},"dashboard":{"uid":"abc","title":"Ops"}}`)
+	}))
+	defer server.Close()
+
+	dryRun, plan := client.DryRun()
+	dashboard, err := dryRun.GetDashboardByUID("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboard.Model["title"] = "Ops 2"
+	if _, err := dryRun.SaveDashboard(&DashboardSaveOpts{Model: dashboard.Model, Overwrite: true}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := dryRun.NewDataSource(&DataSource{
+		Name:              "prom",
+		BasicAuthPassword: "hunter2",
+		SecureJSONData:    SecureJSONData{SecretKey: "s3cr3t"},
+	})
+	if err != nil || id != 0 {
+		t.Fatalf("Expected a zero id and no error, got %d, %v", id, err)
+	}
+	if err := dryRun.DeleteFolderByUID("f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dryRun.WithOrgID(2).RemoveOrgUser(2, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := plan.Requests()
+	if len(requests) != 4 {
+		t.Fatalf("Expected 4 planned writes, got %+v", requests)
+	}
+	expected := []PlannedRequest{
+		{Operation: "SaveDashboard", Method: "POST", Path: "/api/dashboards/db"},
+		{Operation: "NewDataSource", Method: "POST", Path: "/api/datasources"},
+		{Operation: "DeleteFolderByUID", Method: "DELETE", Path: "/api/folders/f"},
+		{Operation: "RemoveOrgUser", Method: "DELETE", Path: "/api/orgs/2/users/5", OrgID: 2},
+	}
+	for i, r := range requests {
+		r.Body = nil
+		if r != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], r)
+		}
+	}
+
+	text := plan.String()
+	for _, s := range []string{"1. POST /api/dashboards/db (SaveDashboard)", `"title": "Ops 2"`, "4. DELETE /api/orgs/2/users/5 (RemoveOrgUser) in org 2"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("Expected %q in the plan:\n%s", s, text)
+		}
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "s3cr3t") || strings.Contains(text, "hunter2") {
+		t.Errorf("Secrets should be redacted from plans: %s", data)
+	}
+	var decoded []PlannedRequest
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 4 {
+		t.Errorf("Expected a JSON array of 4 requests, got %s", data)
+	}
+
+	if _, err := client.GetDashboardByUID("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if client.plan != nil {
+		t.Errorf("DryRun should not change the original client")
+	}
+	plan.Reset()
+	if plan.String() != "No changes.\n" {
+		t.Errorf("Unexpected empty plan %q", plan.String())
+	}
+}
+
+func TestDryRunSendsQueries(t *testing.T) {
+	var sent []string
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"results":{}}`)
+	}))
+	defer server.Close()
+
+	dryRun, plan := client.DryRun()
+	if _, err := dryRun.QueryDataSources(QueryRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dryRun.DataSourceProxy("es", "POST", "logs/_search", nil, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dryRun.DataSourceProxy("es", "DELETE", "logs", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 1 || sent[0] != "POST /api/ds/query" {
+		t.Errorf("Queries should be sent in dry-run mode, got %v", sent)
+	}
+	// The proxied API may write, whatever the method.
+	if requests := plan.Requests(); len(requests) != 2 || requests[0].Method != "POST" || requests[1].Method != "DELETE" {
+		t.Errorf("Proxied requests should be planned, got %+v", requests)
+	}
+}
@@ -0,0 +1,173 @@
+package gapi
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// EndpointClass groups requests for rate limiting.
+type EndpointClass string
+
+const (
+	// EndpointRead covers GET, HEAD and OPTIONS requests.
+	EndpointRead EndpointClass = "read"
+	// EndpointWrite covers every other request.
+	EndpointWrite EndpointClass = "write"
+)
+
+func endpointClass(method string) EndpointClass {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return EndpointRead
+	}
+	return EndpointWrite
+}
+
+// Limit caps the rate and concurrency of requests.
+type Limit struct {
+	// Rate is the number of requests allowed per second on average. Zero
+	// means no rate limit.
+	Rate float64
+	// Burst is the number of requests allowed at once, above Rate.
+	// Defaults to 1.
+	Burst int
+	// MaxInFlight is the number of requests allowed to wait for a response
+	// at the same time. Zero means no limit.
+	MaxInFlight int
+}
+
+// RateLimit configures the limits of a client. Reads and writes share the
+// default limit unless overridden.
+type RateLimit struct {
+	Limit
+	// Read overrides the default limit for reads.
+	Read *Limit
+	// Write overrides the default limit for writes.
+	Write *Limit
+	// OnWait, if set, is called each time a request was delayed by the
+	// limits, with the time it waited.
+	OnWait func(class EndpointClass, waited time.Duration)
+}
+
+// RateLimitStats accounts for the time requests spent waiting for the limits
+// of a client.
+type RateLimitStats struct {
+	Requests int64
+	// Delayed is the number of requests that had to wait.
+	Delayed  int64
+	WaitTime time.Duration
+	MaxWait  time.Duration
+}
+
+type classLimiter struct {
+	bucket   *rate.Limiter
+	inFlight chan struct{}
+}
+
+func newClassLimiter(l Limit) *classLimiter {
+	cl := &classLimiter{}
+	if l.Rate > 0 {
+		burst := l.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		cl.bucket = rate.NewLimiter(rate.Limit(l.Rate), burst)
+	}
+	if l.MaxInFlight > 0 {
+		cl.inFlight = make(chan struct{}, l.MaxInFlight)
+	}
+	return cl
+}
+
+type requestLimiter struct {
+	classes map[EndpointClass]*classLimiter
+	onWait  func(class EndpointClass, waited time.Duration)
+
+	mu    sync.Mutex
+	stats map[EndpointClass]RateLimitStats
+}
+
+func newRequestLimiter(cfg RateLimit) *requestLimiter {
+	shared := newClassLimiter(cfg.Limit)
+	limiter := &requestLimiter{
+		classes: map[EndpointClass]*classLimiter{EndpointRead: shared, EndpointWrite: shared},
+		onWait:  cfg.OnWait,
+		stats:   map[EndpointClass]RateLimitStats{},
+	}
+	if cfg.Read != nil {
+		limiter.classes[EndpointRead] = newClassLimiter(*cfg.Read)
+	}
+	if cfg.Write != nil {
+		limiter.classes[EndpointWrite] = newClassLimiter(*cfg.Write)
+	}
+	return limiter
+}
+
+// acquire waits until the request is allowed by the limits of its class. The
+// returned function must be called once the response has been received.
+func (l *requestLimiter) acquire(req *http.Request) (func(), error) {
+	class := endpointClass(req.Method)
+	cl := l.classes[class]
+	start := time.Now()
+
+	if cl.bucket != nil {
+		if err := cl.bucket.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	release := func() {}
+	if cl.inFlight != nil {
+		select {
+		case cl.inFlight <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		release = func() { <-cl.inFlight }
+	}
+
+	waited := time.Since(start)
+	// Waits below a millisecond are the cost of checking the limits.
+	delayed := waited >= time.Millisecond
+	l.mu.Lock()
+	stats := l.stats[class]
+	stats.Requests++
+	if delayed {
+		stats.Delayed++
+		stats.WaitTime += waited
+		if waited > stats.MaxWait {
+			stats.MaxWait = waited
+		}
+	}
+	l.stats[class] = stats
+	l.mu.Unlock()
+
+	if delayed && l.onWait != nil {
+		l.onWait(class, waited)
+	}
+	return release, nil
+}
+
+// SetRateLimit limits the requests made by the client, and by the copies of
+// it made afterwards with WithOrgID. The in flight limit applies until the
+// response headers are received.
+func (c *Client) SetRateLimit(cfg RateLimit) {
+	c.limiter = newRequestLimiter(cfg)
+}
+
+// RateLimitStats returns the time spent waiting for the limits set with
+// SetRateLimit, per class of endpoint.
+func (c *Client) RateLimitStats() map[EndpointClass]RateLimitStats {
+	stats := map[EndpointClass]RateLimitStats{}
+	if c.limiter == nil {
+		return stats
+	}
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+	for class, s := range c.limiter.stats {
+		stats[class] = s
+	}
+	return stats
+}
@@ -0,0 +1,82 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	server, client := gapiTestTools(200, `{"id":1,"name":"Main Org."}`)
+	defer server.Close()
+
+	waits := int64(0)
+	client.SetRateLimit(RateLimit{
+		Limit: Limit{Rate: 100, Burst: 1},
+		OnWait: func(class EndpointClass, waited time.Duration) {
+			atomic.AddInt64(&waits, 1)
+		},
+	})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Org(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("5 requests at 100 per second should take about 40ms, took %s", elapsed)
+	}
+
+	stats := client.RateLimitStats()[EndpointRead]
+	if stats.Requests != 5 || stats.Delayed == 0 || stats.WaitTime == 0 || stats.MaxWait > stats.WaitTime {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if atomic.LoadInt64(&waits) != stats.Delayed {
+		t.Errorf("OnWait should be called for each delayed request")
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var inFlight, maxInFlight int64
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+		for {
+			max := atomic.LoadInt64(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"id":1}`)
+	}))
+	defer server.Close()
+
+	client.SetRateLimit(RateLimit{
+		Read:  &Limit{MaxInFlight: 2},
+		Write: &Limit{MaxInFlight: 1},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.WithOrgID(1).Org(1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("Expected at most 2 reads in flight, got %d", maxInFlight)
+	}
+	if _, ok := client.RateLimitStats()[EndpointWrite]; ok {
+		t.Error("No write should have been accounted for")
+	}
+}
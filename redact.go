@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Redacted replaces the values of secret fields in the request bodies of
+// plans and audit events, and in the cassettes of gapitest.
+const Redacted = "[REDACTED]"
+
+// secretFields are the JSON fields, compared case-insensitively, whose string
+// values are redacted, unless empty. All the strings of an object held by such a field are
+// redacted, e.g. those of secureJsonData.
+var secretFields = map[string]bool{
+	"password":          true,
+	"basicauthpassword": true,
+	"oldpassword":       true,
+	"newpassword":       true,
+	"confirmnew":        true,
+	"securejsondata":    true,
+	"accesskey":         true,
+	"secretkey":         true,
+	"key":               true,
+	"token":             true,
+	"apikey":            true,
+}
+
+// RedactJSON returns the JSON document data with the values of its secret
+// fields, e.g. passwords and secureJsonData, replaced by Redacted. It fails
+// when data is not JSON.
+func RedactJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactSecrets(v, false))
+}
+
+// redactJSON decodes a JSON document and redacts its secrets. It reports
+// whether data is JSON.
+func redactJSON(data []byte) (interface{}, bool) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, false
+	}
+	return redactSecrets(v, false), true
+}
+
+func redactSecrets(v interface{}, secret bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			v[k] = redactSecrets(field, secret || secretFields[strings.ToLower(k)])
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactSecrets(item, secret)
+		}
+	case string:
+		if secret && v != "" {
+			return Redacted
+		}
+	}
+	return v
+}
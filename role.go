@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role is the role of a user within an organization.
+type Role string
+
+const (
+	// RoleNone grants no basic permissions within the organization.
+	RoleNone   Role = "None"
+	RoleViewer Role = "Viewer"
+	RoleEditor Role = "Editor"
+	RoleAdmin  Role = "Admin"
+)
+
+// roleRanks orders the roles from least to most privileged.
+var roleRanks = map[Role]int{
+	RoleNone:   0,
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ParseRole returns the role named s, ignoring case, so that "editor" parses
+// as RoleEditor.
+func ParseRole(s string) (Role, error) {
+	for role := range roleRanks {
+		if strings.EqualFold(string(role), strings.TrimSpace(s)) {
+			return role, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid role %q, expected one of None, Viewer, Editor or Admin", s)
+}
+
+// Validate returns an error unless r is one of the known roles, spelled the
+// way Grafana expects it.
+func (r Role) Validate() error {
+	if _, ok := roleRanks[r]; !ok {
+		return fmt.Errorf("Invalid role %q, expected one of None, Viewer, Editor or Admin", string(r))
+	}
+	return nil
+}
+
+// IsValid reports whether r is one of the known roles.
+func (r Role) IsValid() bool {
+	return r.Validate() == nil
+}
+
+// AtLeast reports whether r grants at least the permissions of other, e.g.
+// RoleAdmin.AtLeast(RoleEditor) is true. Unknown roles are never at least
+// anything.
+func (r Role) AtLeast(other Role) bool {
+	rank, ok := roleRanks[r]
+	otherRank, otherOk := roleRanks[other]
+	return ok && otherOk && rank >= otherRank
+}
@@ -0,0 +1,52 @@
+package gapi
+
+import "testing"
+
+func TestParseRole(t *testing.T) {
+	for input, expected := range map[string]Role{
+		"editor":  RoleEditor,
+		"Viewer":  RoleViewer,
+		" ADMIN ": RoleAdmin,
+		"none":    RoleNone,
+	} {
+		role, err := ParseRole(input)
+		if err != nil || role != expected {
+			t.Errorf("ParseRole(%q) = %q, %v, expected %q", input, role, err, expected)
+		}
+	}
+
+	if _, err := ParseRole("Owner"); err == nil {
+		t.Error("Unknown roles should not parse")
+	}
+}
+
+func TestRoleValidate(t *testing.T) {
+	if err := RoleEditor.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := Role("editor").Validate(); err == nil {
+		t.Error("Roles should be validated case sensitively")
+	}
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	if !RoleAdmin.AtLeast(RoleEditor) || !RoleEditor.AtLeast(RoleEditor) {
+		t.Error("Admin and Editor should be at least Editor")
+	}
+	if RoleViewer.AtLeast(RoleEditor) || RoleNone.AtLeast(RoleViewer) {
+		t.Error("Viewer and None should not be at least Editor")
+	}
+	if Role("Owner").AtLeast(RoleNone) {
+		t.Error("Unknown roles should not be at least anything")
+	}
+}
+
+func TestAddOrgUserInvalidRole(t *testing.T) {
+	server, client := gapiTestTools(200, addOrgUserJSON)
+	defer server.Close()
+
+	err := client.AddOrgUser(int64(1), "admin@localhost", "editor")
+	if err == nil {
+		t.Error("Invalid roles should be rejected before reaching Grafana")
+	}
+}
@@ -0,0 +1,138 @@
+package gapi
+
+import "net/url"
+
+// The interfaces below group the methods of Client by resource so that code
+// depending on part of the API can accept a fake instead of a Client, e.g. the
+// Mock of the gapitest package.
+
+type DashboardService interface {
+	SaveDashboard(d *DashboardSaveOpts) (*DashboardSaveResponse, error)
+	GetDashboardByUID(uid string) (*Dashboard, error)
+	DeleteDashboardByUID(uid string) error
+}
+
+type DataSourceService interface {
+	NewDataSource(s *DataSource) (int64, error)
+	UpdateDataSource(s *DataSource) error
+	DataSource(id int64) (*DataSource, error)
+	DataSources() ([]*DataSource, error)
+	DataSourceByUID(uid string) (*DataSource, error)
+	DataSourceByName(name string) (*DataSource, error)
+	DeleteDataSource(id int64) error
+	RotateDataSourceSecrets(id int64, secrets DataSourceSecrets) (map[string]bool, error)
+	EnsureDataSource(ds *DataSource) (*DataSource, EnsureAction, error)
+	DataSourcePermissions(id int64) (*DataSourcePermissions, error)
+	EnableDataSourcePermissions(id int64) error
+	DisableDataSourcePermissions(id int64) error
+	AddDataSourcePermission(id int64, perm *DataSourcePermissionAddOpts) error
+	RemoveDataSourcePermission(id, permissionId int64) error
+	QueryDataSources(q QueryRequest) (*QueryResponse, error)
+	DataSourceProxy(uid, method, proxyPath string, query url.Values, body []byte) ([]byte, error)
+}
+
+type FolderService interface {
+	GetAllFolders() ([]Folder, error)
+	GetFolderByUID(uid string) (*Folder, error)
+	GetFolderByID(id int) (*Folder, error)
+	CreateFolder(folder *FolderCreateOpts) (*Folder, error)
+	UpdateFolder(folder *FolderUpdateOpts) (*Folder, error)
+	DeleteFolderByUID(uid string) error
+	GetFolderChildren(parentUid string) ([]Folder, error)
+	GetFolderAncestors(uid string) ([]Folder, error)
+	MoveFolder(uid, parentUid string) (*Folder, error)
+	GetFolderTree() ([]*FolderNode, error)
+	EnsureFolder(opts FolderCreateOpts) (*Folder, EnsureAction, error)
+}
+
+type OrgService interface {
+	Orgs() ([]Org, error)
+	OrgByName(name string) (Org, error)
+	Org(id int64) (Org, error)
+	NewOrg(name string) (int64, error)
+	UpdateOrg(id int64, name string) error
+	DeleteOrg(id int64) error
+	EnsureOrg(name string) (Org, EnsureAction, error)
+	UpdateOrgAddress(id int64, address OrgAddress) error
+	OrgQuotas(id int64) ([]Quota, error)
+	UpdateOrgQuota(id int64, target string, limit int64) error
+	CurrentOrg() (Org, error)
+	UpdateCurrentOrg(name string) error
+	UpdateCurrentOrgAddress(address OrgAddress) error
+	OrgPreferences() (Preferences, error)
+	UpdateOrgPreferences(prefs Preferences) error
+	CurrentOrgQuotas() ([]Quota, error)
+
+	OrgUsers(orgId int64) ([]OrgUser, error)
+	AddOrgUser(orgId int64, user string, role Role) error
+	UpdateOrgUser(orgId, userId int64, role Role) error
+	RemoveOrgUser(orgId, userId int64) error
+	SearchOrgUsers(orgId int64, query string, perPage, page int) (*OrgUserSearchPage, error)
+	SyncOrgMembers(orgId int64, desired map[string]Role, opts SyncOrgMembersOpts) (*SyncOrgMembersReport, error)
+	CurrentOrgUsers() ([]OrgUser, error)
+	CurrentOrgUsersLookup(query string, limit int) ([]OrgUserLookup, error)
+	AddCurrentOrgUser(user string, role Role) error
+	UpdateCurrentOrgUser(userId int64, role Role) error
+	RemoveCurrentOrgUser(userId int64) error
+
+	CreateOrgInvite(invite OrgInviteOpts) error
+	OrgInvites() ([]OrgInvite, error)
+	RevokeOrgInvite(code string) error
+	OrgInviteByCode(code string) (OrgInvite, error)
+	CompleteOrgInvite(opts CompleteInviteOpts) error
+}
+
+// UserService holds the user methods of Client but IterateUsers, which is
+// built on SearchUsers.
+type UserService interface {
+	Users() ([]User, error)
+	UserByEmail(email string) (User, error)
+	UserByID(id int64) (User, error)
+	SearchUsers(query string, perPage, page int) (*UserSearchPage, error)
+	UpdateUser(user User) error
+	UserOrgs(id int64) ([]UserOrg, error)
+	UserTeams(id int64) ([]Team, error)
+
+	CreateUser(user User) (int64, error)
+	DeleteUser(id int64) error
+	UpdateUserPassword(id int64, password string) error
+	UpdateUserPermissions(id int64, isGrafanaAdmin bool) error
+	DisableUser(id int64) error
+	EnableUser(id int64) error
+	LogoutUser(id int64) error
+	UserAuthTokens(id int64) ([]UserAuthToken, error)
+	RevokeUserAuthToken(id, tokenId int64) error
+	UserQuotas(id int64) ([]Quota, error)
+
+	CurrentUser() (User, error)
+	UpdateCurrentUser(user User) error
+	ChangeCurrentUserPassword(oldPassword, newPassword string) error
+	CurrentUserOrgs() ([]UserOrg, error)
+	SwitchCurrentUserOrg(orgId int64) error
+	CurrentUserTeams() ([]Team, error)
+	StarDashboard(uid string) error
+	UnstarDashboard(uid string) error
+	CurrentUserPreferences() (Preferences, error)
+	UpdateCurrentUserPreferences(prefs Preferences) error
+	CurrentUserAuthTokens() ([]UserAuthToken, error)
+	RevokeCurrentUserAuthToken(tokenId int64) error
+}
+
+type AlertNotificationService interface {
+	AlertNotification(id int64) (*AlertNotification, error)
+	NewAlertNotification(a *AlertNotification) (int64, error)
+	UpdateAlertNotification(a *AlertNotification) error
+	DeleteAlertNotification(id int64) error
+}
+
+// Services is implemented by Client and holds every service interface.
+type Services interface {
+	DashboardService
+	DataSourceService
+	FolderService
+	OrgService
+	UserService
+	AlertNotificationService
+}
+
+var _ Services = (*Client)(nil)
@@ -1,10 +1,10 @@
 package gapi
 
 import (
-	"encoding/json"
-	"errors"
-	"io/ioutil"
+	"fmt"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 type User struct {
@@ -12,65 +12,194 @@ type User struct {
 	Email    string `json:"email,omitempty"`
 	Name     string `json:"name,omitempty"`
 	Login    string `json:"login,omitempty"`
+	Theme    string `json:"theme,omitempty"`
 	Password string `json:"password,omitempty"`
-	IsAdmin  bool   `json:"isAdmin,omitempty"`
+	// IsAdmin is the server admin flag as reported by user lists. Single
+	// user lookups report it as IsGrafanaAdmin and set both.
+	IsAdmin        bool `json:"isAdmin,omitempty"`
+	IsGrafanaAdmin bool `json:"isGrafanaAdmin,omitempty"`
+	IsDisabled     bool `json:"isDisabled,omitempty"`
+}
+
+// userUpdate is the body of user updates. Empty email, name, login and theme
+// are left unchanged by Grafana.
+type userUpdate struct {
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Login string `json:"login,omitempty"`
+	Theme string `json:"theme,omitempty"`
+}
+
+// UserSearch is a user as returned by SearchUsers.
+type UserSearch struct {
+	Id            int64     `json:"id"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	Login         string    `json:"login"`
+	AvatarUrl     string    `json:"avatarUrl"`
+	IsAdmin       bool      `json:"isAdmin"`
+	IsDisabled    bool      `json:"isDisabled"`
+	LastSeenAt    time.Time `json:"lastSeenAt"`
+	LastSeenAtAge string    `json:"lastSeenAtAge"`
+	AuthLabels    []string  `json:"authLabels"`
+}
+
+// UserSearchPage is a single page of SearchUsers results.
+type UserSearchPage struct {
+	TotalCount int64        `json:"totalCount"`
+	Users      []UserSearch `json:"users"`
+	Page       int          `json:"page"`
+	PerPage    int          `json:"perPage"`
+}
+
+// UserOrg is an organization a user is a member of.
+type UserOrg struct {
+	OrgId int64  `json:"orgId"`
+	Name  string `json:"name"`
+	Role  Role   `json:"role"`
+}
+
+type Team struct {
+	Id          int64  `json:"id"`
+	OrgId       int64  `json:"orgId"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	AvatarUrl   string `json:"avatarUrl"`
+	MemberCount int64  `json:"memberCount"`
 }
 
 func (c *Client) Users() ([]User, error) {
 	users := make([]User, 0)
-	req, err := c.newRequest("GET", "/api/users", nil, nil)
-	if err != nil {
-		return users, err
-	}
-	resp, err := c.Do(req)
-	if err != nil {
-		return users, err
-	}
-	if resp.StatusCode != 200 {
-		return users, errors.New(resp.Status)
-	}
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return users, err
-	}
-	err = json.Unmarshal(data, &users)
-	if err != nil {
-		return users, err
-	}
+	err := c.getJSON("Users", "/api/users", &users)
 	return users, err
 }
 
 func (c *Client) UserByEmail(email string) (User, error) {
-	user := User{}
 	query := url.Values{}
 	query.Add("loginOrEmail", email)
-	req, err := c.newRequest("GET", "/api/users/lookup", query, nil)
-	if err != nil {
-		return user, err
+	return c.userBy("UserByEmail", "/api/users/lookup", query)
+}
+
+func (c *Client) UserByID(id int64) (User, error) {
+	return c.userBy("UserByID", fmt.Sprintf("/api/users/%d", id), nil)
+}
+
+// userBy looks up a single user. Lookups report the server admin flag as
+// isGrafanaAdmin, which is copied to IsAdmin as user lists call it.
+func (c *Client) userBy(name, path string, query url.Values) (User, error) {
+	user := User{}
+	err := c.sendJSON(name, "GET", path, query, nil, &user)
+	user.IsAdmin = user.IsGrafanaAdmin
+	return user, err
+}
+
+// SearchUsers returns a page of the users whose login, email or name match
+// query. Pages start at 1.
+func (c *Client) SearchUsers(query string, perPage, page int) (*UserSearchPage, error) {
+	params := url.Values{}
+	if query != "" {
+		params.Add("query", query)
 	}
-	resp, err := c.Do(req)
-	if err != nil {
-		return user, err
+	params.Add("perpage", strconv.Itoa(perPage))
+	params.Add("page", strconv.Itoa(page))
+	result := &UserSearchPage{}
+	if err := c.sendJSON("SearchUsers", "GET", "/api/users/search", params, nil, result); err != nil {
+		return nil, err
 	}
-	if resp.StatusCode != 200 {
-		return user, errors.New(resp.Status)
+	return result, nil
+}
+
+// UserIterator pages through the results of SearchUsers.
+//
+//	it := client.IterateUsers("", 100)
+//	for it.Next() {
+//		user := it.User()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type UserIterator struct {
+	client  *Client
+	query   string
+	perPage int
+	page    int
+	users   []UserSearch
+	seen    int64
+	total   int64
+	done    bool
+	err     error
+}
+
+// IterateUsers returns an iterator over all users matching query, fetching
+// perPage users per request.
+func (c *Client) IterateUsers(query string, perPage int) *UserIterator {
+	return &UserIterator{client: c, query: query, perPage: perPage}
+}
+
+// Next advances to the next user, fetching the next page when needed. It
+// returns false when there are no more users or a request failed.
+func (it *UserIterator) Next() bool {
+	if len(it.users) > 1 {
+		it.users = it.users[1:]
+		return true
 	}
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return user, err
+	it.users = nil
+	if it.done || it.err != nil {
+		return false
 	}
-	tmp := struct {
-		Id       int64  `json:"id,omitempty"`
-		Email    string `json:"email,omitempty"`
-		Name     string `json:"name,omitempty"`
-		Login    string `json:"login,omitempty"`
-		Password string `json:"password,omitempty"`
-		IsAdmin  bool   `json:"isGrafanaAdmin,omitempty"`
-	}{}
-	err = json.Unmarshal(data, &tmp)
+	it.page++
+	result, err := it.client.SearchUsers(it.query, it.perPage, it.page)
 	if err != nil {
-		return user, err
+		it.err = err
+		return false
 	}
-	user = User(tmp)
-	return user, err
+	it.total = result.TotalCount
+	it.seen += int64(len(result.Users))
+	if len(result.Users) < it.perPage || it.seen >= it.total {
+		it.done = true
+	}
+	if len(result.Users) == 0 {
+		return false
+	}
+	it.users = result.Users
+	return true
+}
+
+// User returns the current user.
+func (it *UserIterator) User() UserSearch {
+	return it.users[0]
+}
+
+// TotalCount returns the number of matching users reported by the last page.
+func (it *UserIterator) TotalCount() int64 {
+	return it.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+// UpdateUser updates the email, name, login and theme of the user. Empty
+// email, name, login and theme are left unchanged.
+func (c *Client) UpdateUser(user User) error {
+	body := userUpdate{
+		Email: user.Email,
+		Name:  user.Name,
+		Login: user.Login,
+		Theme: user.Theme,
+	}
+	return c.sendJSON("UpdateUser", "PUT", fmt.Sprintf("/api/users/%d", user.Id), nil, body, nil)
+}
+
+func (c *Client) UserOrgs(id int64) ([]UserOrg, error) {
+	orgs := make([]UserOrg, 0)
+	err := c.getJSON("UserOrgs", fmt.Sprintf("/api/users/%d/orgs", id), &orgs)
+	return orgs, err
+}
+
+func (c *Client) UserTeams(id int64) ([]Team, error) {
+	teams := make([]Team, 0)
+	err := c.getJSON("UserTeams", fmt.Sprintf("/api/users/%d/teams", id), &teams)
+	return teams, err
 }
@@ -1,13 +1,22 @@
 package gapi
 
 import (
-	"github.com/gobs/pretty"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
 	"testing"
+
+	"github.com/gobs/pretty"
 )
 
 const (
 	getUsersJSON       = `[{"id":1,"name":"","login":"admin","email":"admin@localhost","avatarUrl":"/avatar/46d229b033af06a191ff2267bca9ae56","isAdmin":true,"lastSeenAt":"2018-06-28T14:42:24Z","lastSeenAtAge":"\u003c 1m"}]`
 	getUserByEmailJSON = `{"id":1,"email":"admin@localhost","name":"","login":"admin","theme":"","orgId":1,"isGrafanaAdmin":true}`
+	searchUsersJSON    = `{"totalCount":2,"users":[{"id":1,"name":"Admin","login":"admin","email":"admin@localhost","isAdmin":true,"lastSeenAt":"2018-06-28T14:42:24Z","lastSeenAtAge":"\u003c 1m"},{"id":2,"name":"User","login":"user","email":"user@localhost","isAdmin":false,"authLabels":["LDAP"]}],"page":1,"perPage":10}`
+	updateUserJSON     = `{"message":"User updated"}`
+	getUserOrgsJSON    = `[{"orgId":1,"name":"Main Org.","role":"Admin"}]`
+	getUserTeamsJSON   = `[{"id":1,"orgId":1,"name":"team1","email":"","avatarUrl":"/avatar/3fcfe295eae3bcb67a49349377428a66","memberCount":1}]`
 )
 
 func TestUsers(t *testing.T) {
@@ -46,13 +55,126 @@ func TestUserByEmail(t *testing.T) {
 	t.Log(pretty.PrettyFormat(resp))
 
 	user := User{
-		Id:      1,
-		Email:   "admin@localhost",
-		Name:    "",
-		Login:   "admin",
-		IsAdmin: true,
+		Id:             1,
+		Email:          "admin@localhost",
+		Name:           "",
+		Login:          "admin",
+		IsAdmin:        true,
+		IsGrafanaAdmin: true,
 	}
 	if resp != user {
 		t.Error("Not correctly parsing returned user.")
 	}
 }
+
+func TestUserByID(t *testing.T) {
+	server, client := gapiTestTools(200, getUserByEmailJSON)
+	defer server.Close()
+
+	resp, err := client.UserByID(1)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if resp.Id != 1 || resp.Login != "admin" || !resp.IsGrafanaAdmin || !resp.IsAdmin {
+		t.Error("Not correctly parsing returned user.")
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	server, client := gapiTestTools(200, searchUsersJSON)
+	defer server.Close()
+
+	resp, err := client.SearchUsers("admin", 10, 1)
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(pretty.PrettyFormat(resp))
+
+	if resp.TotalCount != 2 || len(resp.Users) != 2 || resp.Users[1].AuthLabels[0] != "LDAP" {
+		t.Error("Not correctly parsing returned user search.")
+	}
+}
+
+func TestIterateUsers(t *testing.T) {
+	pages := []string{
+		`{"totalCount":3,"users":[{"id":1,"login":"a"},{"id":2,"login":"b"}],"page":1,"perPage":2}`,
+		`{"totalCount":3,"users":[{"id":3,"login":"c"}],"page":2,"perPage":2}`,
+	}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 || page > len(pages) {
+			t.Errorf("Unexpected page %d", page)
+			w.WriteHeader(400)
+			return
+		}
+		fmt.Fprint(w, pages[page-1])
+	}))
+	defer server.Close()
+
+	logins := ""
+	it := client.IterateUsers("", 2)
+	for it.Next() {
+		logins += it.User().Login
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if logins != "abc" || it.TotalCount() != 3 {
+		t.Errorf("Expected to iterate over all users, got %q", logins)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	var body map[string]interface{}
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, updateUserJSON)
+	}))
+	defer server.Close()
+
+	err := client.UpdateUser(User{Id: 1, Email: "admin@localhost", Login: "admin", Theme: "light"})
+	if err != nil {
+		t.Error(err)
+	}
+	if _, ok := body["name"]; ok || body["email"] != "admin@localhost" || body["login"] != "admin" || body["theme"] != "light" {
+		t.Errorf("Unexpected update %v", body)
+	}
+
+	if err = client.UpdateUser(User{Id: 1, Name: "Admin"}); err != nil {
+		t.Error(err)
+	}
+	if _, ok := body["email"]; ok || len(body) != 1 || body["name"] != "Admin" {
+		t.Errorf("Empty email, name, login and theme should be omitted, got %v", body)
+	}
+}
+
+func TestUserOrgs(t *testing.T) {
+	server, client := gapiTestTools(200, getUserOrgsJSON)
+	defer server.Close()
+
+	resp, err := client.UserOrgs(1)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 1 || resp[0] != (UserOrg{OrgId: 1, Name: "Main Org.", Role: "Admin"}) {
+		t.Error("Not correctly parsing returned user orgs.")
+	}
+}
+
+func TestUserTeams(t *testing.T) {
+	server, client := gapiTestTools(200, getUserTeamsJSON)
+	defer server.Close()
+
+	resp, err := client.UserTeams(1)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(resp) != 1 || resp[0].Name != "team1" || resp[0].MemberCount != 1 {
+		t.Error("Not correctly parsing returned user teams.")
+	}
+}
@@ -0,0 +1,225 @@
+package gapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedVersion is the cause of errors returned when the server runs a
+// Grafana version lacking a capability a method relies on. Check for it with
+// errors.Cause.
+var ErrUnsupportedVersion = errors.New("Unsupported Grafana version")
+
+// Version is a Grafana version such as 10.2.1 or 11.0.0-beta1.
+type Version struct {
+	Major, Minor, Patch int
+	// Pre is the pre-release suffix, e.g. "beta1" or "pre".
+	Pre string
+}
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:[-+~](.+))?$`)
+
+// ParseVersion parses a Grafana version. Missing minor and patch numbers are
+// zero.
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("Invalid Grafana version %q", s)
+	}
+	v := Version{Pre: m[4]}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 when v is older than, the same as or newer than
+// other. Pre-releases are older than the release they precede.
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == other.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case other.Pre == "":
+		return -1
+	case v.Pre < other.Pre:
+		return -1
+	}
+	return 1
+}
+
+// AtLeast reports whether v is other or newer.
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// versionCache holds the version of the server, shared by the copies of a
+// client made by WithOrgID.
+type versionCache struct {
+	mu      sync.Mutex
+	version *Version
+}
+
+// Version returns the version of the server. It is fetched once and cached
+// for the lifetime of the client.
+func (c *Client) Version() (Version, error) {
+	if c.version != nil {
+		c.version.mu.Lock()
+		defer c.version.mu.Unlock()
+		if c.version.version != nil {
+			return *c.version.version, nil
+		}
+	}
+
+	health := struct {
+		Version string `json:"version"`
+	}{}
+	if err := c.getJSON("Version", "/api/health", &health); err != nil {
+		return Version{}, err
+	}
+	raw := health.Version
+	if raw == "" {
+		buildInfo, err := c.BuildInfo()
+		if err != nil {
+			return Version{}, err
+		}
+		raw = buildInfo.Version
+	}
+	v, err := ParseVersion(raw)
+	if err != nil {
+		return Version{}, err
+	}
+	if c.version != nil {
+		c.version.version = &v
+	}
+	return v, nil
+}
+
+// Capability is a feature only some Grafana versions have.
+type Capability string
+
+const (
+	CapabilityUnifiedAlerting Capability = "unified-alerting"
+	CapabilityLegacyAlerting  Capability = "legacy-alerting"
+	CapabilityServiceAccounts Capability = "service-accounts"
+	CapabilityAPIKeys         Capability = "api-keys"
+	CapabilityNestedFolders   Capability = "nested-folders"
+	CapabilityAccessControl   Capability = "access-control"
+	CapabilityDataSourceQuery Capability = "datasource-query"
+)
+
+type versionRange struct {
+	min Version
+	// max is the first version without the capability, if any.
+	max *Version
+}
+
+var (
+	capabilitiesMu sync.RWMutex
+	capabilities   = map[Capability]versionRange{}
+)
+
+func init() {
+	for _, c := range []struct {
+		capability Capability
+		min, max   string
+	}{
+		{CapabilityUnifiedAlerting, "8.0.0", ""},
+		{CapabilityLegacyAlerting, "0.0.0", "11.0.0"},
+		{CapabilityServiceAccounts, "8.5.0", ""},
+		{CapabilityAPIKeys, "0.0.0", "12.0.0"},
+		{CapabilityNestedFolders, "10.0.0", ""},
+		{CapabilityAccessControl, "8.0.0", ""},
+		{CapabilityDataSourceQuery, "8.0.0", ""},
+	} {
+		if err := RegisterCapability(c.capability, c.min, c.max); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterCapability registers, or replaces, a capability available from
+// version min up to but excluding version max. An empty max means the
+// capability has not been removed.
+func RegisterCapability(capability Capability, min, max string) error {
+	r := versionRange{}
+	var err error
+	if r.min, err = ParseVersion(min); err != nil {
+		return err
+	}
+	if max != "" {
+		v, err := ParseVersion(max)
+		if err != nil {
+			return err
+		}
+		r.max = &v
+	}
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	capabilities[capability] = r
+	return nil
+}
+
+// Supports reports whether the version of the server has a capability.
+func (c *Client) Supports(capability Capability) (bool, error) {
+	capabilitiesMu.RLock()
+	r, ok := capabilities[capability]
+	capabilitiesMu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("Unknown capability %q", capability)
+	}
+	v, err := c.Version()
+	if err != nil {
+		return false, err
+	}
+	return v.AtLeast(r.min) && (r.max == nil || !v.AtLeast(*r.max)), nil
+}
+
+// RequireCapability returns an error caused by ErrUnsupportedVersion when the
+// server lacks a capability.
+func (c *Client) RequireCapability(capability Capability) error {
+	ok, err := c.Supports(capability)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		v, _ := c.Version()
+		return errors.Wrapf(ErrUnsupportedVersion, "%s is not available in Grafana %s", capability, v)
+	}
+	return nil
+}
+
+// explainNotFound turns a 404 returned by an endpoint that relies on a
+// capability into an error caused by ErrUnsupportedVersion when the server
+// lacks the capability. Other errors, and 404s of servers that have the
+// capability or whose version is unknown, are returned as is.
+func (c *Client) explainNotFound(err error, capability Capability) error {
+	if !isGrafanaStatus(err, 404) {
+		return err
+	}
+	if ok, verr := c.Supports(capability); verr != nil || ok {
+		return err
+	}
+	return c.RequireCapability(capability)
+}
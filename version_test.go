@@ -0,0 +1,157 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]Version{
+		"10.2.1":          {Major: 10, Minor: 2, Patch: 1},
+		"v9.5":            {Major: 9, Minor: 5},
+		"11.0.0-beta1":    {Major: 11, Pre: "beta1"},
+		"10.3.0-63588pre": {Major: 10, Minor: 3, Pre: "63588pre"},
+	}
+	for s, expected := range cases {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if v != expected {
+			t.Errorf("Parsing %s: expected %v, got %v", s, expected, v)
+		}
+	}
+	if _, err := ParseVersion("latest"); err == nil {
+		t.Error("Invalid versions should be rejected")
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"8.5.27", "9.0.0-beta2", "9.0.0", "9.0.1", "10.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		older, _ := ParseVersion(ordered[i-1])
+		newer, _ := ParseVersion(ordered[i])
+		if older.Compare(newer) != -1 || newer.Compare(older) != 1 || !newer.AtLeast(older) {
+			t.Errorf("%s should be older than %s", older, newer)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			fmt.Fprint(w, `{"commit":"abc123","database":"ok","version":"10.2.1"}`)
+		case "/api/frontend/settings":
+			fmt.Fprint(w, `{"buildInfo":{"version":"10.2.1","commit":"abc123","edition":"Enterprise","env":"production"}}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Database != "ok" || health.Version != "10.2.1" || health.BuildInfo.Edition != "Enterprise" {
+		t.Errorf("Not correctly parsing health %v", health)
+	}
+}
+
+func TestHealthDatabaseFailing(t *testing.T) {
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			w.WriteHeader(503)
+			fmt.Fprint(w, `{"commit":"abc123","database":"failing","version":"10.2.1"}`)
+		case "/api/frontend/settings":
+			fmt.Fprint(w, `{"buildInfo":{"version":"10.2.1"}}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	health, err := client.Health()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.Database != "failing" || health.BuildInfo.Version != "10.2.1" {
+		t.Errorf("Expected an unhealthy database, got %v", health)
+	}
+}
+
+func TestVersionIsCached(t *testing.T) {
+	requests := 0
+	server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"database":"ok","version":"9.5.2"}`)
+	}))
+	defer server.Close()
+
+	for _, c := range []*Client{client, client.WithOrgID(2)} {
+		v, err := c.Version()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != (Version{Major: 9, Minor: 5, Patch: 2}) {
+			t.Errorf("Unexpected version %v", v)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Version should be fetched once, got %d requests", requests)
+	}
+
+	if ok, err := client.Supports(CapabilityNestedFolders); err != nil || ok {
+		t.Errorf("Grafana 9.5 should not support nested folders: %v %v", ok, err)
+	}
+	if ok, err := client.Supports(CapabilityLegacyAlerting); err != nil || !ok {
+		t.Errorf("Grafana 9.5 should support legacy alerting: %v %v", ok, err)
+	}
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	for version, unsupported := range map[string]bool{"9.5.2": true, "10.2.0": false} {
+		server, client := gapiTestToolsWithHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/health" {
+				fmt.Fprintf(w, `{"database":"ok","version":"%s"}`, version)
+				return
+			}
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"message":"Not found"}`)
+		}))
+
+		_, err := client.MoveFolder("ops", "parent")
+		if unsupported && errors.Cause(err) != ErrUnsupportedVersion {
+			t.Errorf("Expected an unsupported version error for %s, got %v", version, err)
+		}
+		if !unsupported && !isGrafanaStatus(err, 404) {
+			t.Errorf("Expected the 404 to be kept for %s, got %v", version, err)
+		}
+		server.Close()
+	}
+}
+
+func TestRegisterCapability(t *testing.T) {
+	server, client := gapiTestTools(200, `{"database":"ok","version":"10.2.0"}`)
+	defer server.Close()
+
+	if err := RegisterCapability("test-capability", "10.3.0", ""); err != nil {
+		t.Fatal(err)
+	}
+	err := client.RequireCapability("test-capability")
+	if errors.Cause(err) != ErrUnsupportedVersion {
+		t.Errorf("Expected an unsupported version error, got %v", err)
+	}
+	if _, err := client.Supports("unknown-capability"); err == nil {
+		t.Error("Unknown capabilities should be reported")
+	}
+	if err := RegisterCapability("test-capability", "next", ""); err == nil {
+		t.Error("Invalid versions should be rejected")
+	}
+}